@@ -0,0 +1,24 @@
+// Command nrs-fakeapi serves a local stand-in for the New Relic
+// Synthetics and Alerts REST APIs, for running the provider end to end
+// without New Relic credentials.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/fakeapi"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "The address to listen on")
+	apiKey := flag.String("api-key", "", "The API key to accept; any key if empty")
+	flag.Parse()
+
+	log.Printf("serving New Relic stand-in on %s", *addr)
+	log.Printf("point the provider at it with NEWRELIC_SYNTHETICS_BASE_URL=http://%s%s and NEWRELIC_ALERTS_BASE_URL=http://%s%s",
+		*addr, fakeapi.SyntheticsPath, *addr, fakeapi.AlertsPath)
+
+	log.Fatal(http.ListenAndServe(*addr, fakeapi.New(*apiKey)))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/provider"
+)
+
+// drift reports the nrs_monitor and nrs_alert_condition resources in
+// state files that differ from New Relic. It exits with status 2 if any
+// resource drifted or is missing, and 1 if any couldn't be read.
+func drift(args []string) error {
+	flags := flag.NewFlagSet("drift", flag.ExitOnError)
+	format := flags.String("format", "text", "The report format, text or json")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: terraform-provider-nrs drift [-format text|json] STATE_FILE...\n\n")
+		fmt.Fprintf(os.Stderr, "Reports the nrs_monitor and nrs_alert_condition resources in Terraform state\n")
+		fmt.Fprintf(os.Stderr, "files that differ from New Relic. NEWRELIC_API_KEY must be set.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitCode(2)
+	}
+
+	meta, err := provider.ReadOnlyMeta()
+	if err != nil {
+		return err
+	}
+
+	report, err := provider.Drift(meta, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	drifted, missing, failed := report.Drifted()
+	switch {
+	case failed > 0:
+		return exitCode(1)
+	case drifted > 0 || missing > 0:
+		return exitCode(2)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/provider"
+)
+
+// export writes Terraform configuration for the monitors and alert
+// conditions in New Relic. The provider is configured from the
+// environment, as it is in Terraform, in read_only mode.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	dir := flags.String("dir", ".", "The directory to write configuration, scripts and the import script to")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: terraform-provider-nrs export [-dir DIR]\n\n")
+		fmt.Fprintf(os.Stderr, "Writes Terraform configuration for every Synthetics monitor and alert condition\n")
+		fmt.Fprintf(os.Stderr, "in New Relic, and a script that imports them. NEWRELIC_API_KEY must be set.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	meta, err := provider.ReadOnlyMeta()
+	if err != nil {
+		return err
+	}
+
+	monitors, conditions, err := provider.Export(meta, *dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d monitors and %d alert conditions to %s.\n", monitors, conditions, *dir)
+	fmt.Printf("Run %s after terraform init to import them.\n", filepath.Join(*dir, provider.ExportImportScript))
+
+	return nil
+}
@@ -1,11 +1,45 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/provider"
 	"github.com/hashicorp/terraform/plugin"
 )
 
+// commands are the subcommands run when the binary isn't started by
+// Terraform as a plugin.
+var commands = map[string]func(args []string) error{
+	"drift":  drift,
+	"export": export,
+}
+
+// exitCode is returned by a command to exit with a status without
+// printing an error.
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+			os.Exit(2)
+		}
+		if err := command(os.Args[2:]); err != nil {
+			if code, ok := err.(exitCode); ok {
+				os.Exit(int(code))
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: provider.Provider,
 	})
@@ -0,0 +1,701 @@
+// Package fake is an in-memory stand-in for the New Relic Synthetics,
+// Alerts and Insights APIs, for unit testing resource functions without
+// talking to New Relic.
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+// Client is an in-memory implementation of the monitor, monitor
+// script, alert condition, alert policy, multi-location and NRQL alert
+// condition, location, monitor label and NRQL query operations the
+// provider uses. Its fields may be read and changed directly by tests,
+// e.g. to delete a monitor out of band.
+//
+// Errors injects failures: when Errors has an entry for a method name
+// (e.g. "UpdateMonitor"), that method returns the error without doing
+// anything.
+type Client struct {
+	mu     sync.Mutex
+	nextID uint
+
+	Monitors               map[string]*synthetics.ExtendedMonitor
+	Scripts                map[string]*synthetics.UpdateMonitorScriptArgs
+	Labels                 map[string][]*newrelic.MonitorLabel
+	AlertConditions        map[uint]*synthetics.AlertCondition
+	AlertConditionPolicies map[uint]uint
+	Policies               map[uint]*newrelic.AlertPolicy
+
+	LocationFailureConditions        map[uint]*newrelic.LocationFailureCondition
+	LocationFailureConditionPolicies map[uint]uint
+	NRQLConditions                   map[uint]*newrelic.NRQLCondition
+	NRQLConditionPolicies            map[uint]uint
+	Locations                        []*newrelic.Location
+
+	// QueryResults holds the response to each NRQL query, keyed by
+	// the query. Queries records the queries run, in order.
+	QueryResults map[string]*newrelic.InsightsResponse
+	Queries      []string
+
+	// IgnoreConditionMoves makes UpdateSyntheticsCondition accept a new
+	// policy ID without moving the condition.
+	IgnoreConditionMoves bool
+
+	Errors map[string]error
+}
+
+// NewClient returns an empty Client.
+func NewClient() *Client {
+	return &Client{
+		nextID:                 100,
+		Monitors:               map[string]*synthetics.ExtendedMonitor{},
+		Scripts:                map[string]*synthetics.UpdateMonitorScriptArgs{},
+		Labels:                 map[string][]*newrelic.MonitorLabel{},
+		AlertConditions:        map[uint]*synthetics.AlertCondition{},
+		AlertConditionPolicies: map[uint]uint{},
+		Policies:               map[uint]*newrelic.AlertPolicy{},
+
+		LocationFailureConditions:        map[uint]*newrelic.LocationFailureCondition{},
+		LocationFailureConditionPolicies: map[uint]uint{},
+		NRQLConditions:                   map[uint]*newrelic.NRQLCondition{},
+		NRQLConditionPolicies:            map[uint]uint{},
+		QueryResults:                     map[string]*newrelic.InsightsResponse{},
+
+		Errors: map[string]error{},
+	}
+}
+
+func (c *Client) id() uint {
+	c.nextID++
+	return c.nextID
+}
+
+// copyMonitor returns a copy of a monitor, so that callers can't change
+// the stored one.
+func copyMonitor(monitor *synthetics.ExtendedMonitor) *synthetics.ExtendedMonitor {
+	m := *monitor
+	m.Locations = append([]string(nil), monitor.Locations...)
+	return &m
+}
+
+// GetAllMonitors returns a page of monitors, ordered by ID.
+func (c *Client) GetAllMonitors(offset uint, limit uint) (*synthetics.GetAllMonitorsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetAllMonitors"]; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(c.Monitors))
+	for id := range c.Monitors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resp := &synthetics.GetAllMonitorsResponse{Count: uint(len(ids))}
+	for i := offset; i < uint(len(ids)) && i < offset+limit; i++ {
+		resp.Monitors = append(resp.Monitors, copyMonitor(c.Monitors[ids[i]]))
+	}
+
+	return resp, nil
+}
+
+// GetMonitor returns a monitor.
+func (c *Client) GetMonitor(id string) (*synthetics.ExtendedMonitor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetMonitor"]; err != nil {
+		return nil, err
+	}
+
+	monitor, ok := c.Monitors[id]
+	if !ok {
+		return nil, synthetics.ErrMonitorNotFound
+	}
+
+	return copyMonitor(monitor), nil
+}
+
+// CreateMonitor creates a monitor.
+func (c *Client) CreateMonitor(args *synthetics.CreateMonitorArgs) (*synthetics.ExtendedMonitor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["CreateMonitor"]; err != nil {
+		return nil, err
+	}
+
+	monitor := &synthetics.ExtendedMonitor{
+		ID:                     fmt.Sprintf("monitor-%d", c.id()),
+		Name:                   args.Name,
+		Type:                   args.Type,
+		Frequency:              args.Frequency,
+		URI:                    args.URI,
+		Locations:              append([]string(nil), args.Locations...),
+		Status:                 args.Status,
+		SLAThreshold:           args.SLAThreshold,
+		ValidationString:       args.ValidationString,
+		VerifySSL:              args.VerifySSL,
+		BypassHEADRequest:      args.BypassHEADRequest,
+		TreatRedirectAsFailure: args.TreatRedirectAsFailure,
+	}
+	if monitor.SLAThreshold == 0 {
+		monitor.SLAThreshold = 7
+	}
+	c.Monitors[monitor.ID] = monitor
+
+	return copyMonitor(monitor), nil
+}
+
+// UpdateMonitor updates a monitor. Like New Relic, it leaves
+// locations and the optional settings alone when they aren't sent.
+func (c *Client) UpdateMonitor(id string, args *synthetics.UpdateMonitorArgs) (*synthetics.ExtendedMonitor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateMonitor"]; err != nil {
+		return nil, err
+	}
+
+	monitor, ok := c.Monitors[id]
+	if !ok {
+		return nil, synthetics.ErrMonitorNotFound
+	}
+
+	monitor.Name = args.Name
+	monitor.Frequency = args.Frequency
+	monitor.URI = args.URI
+	monitor.Status = args.Status
+	if args.SLAThreshold != 0 {
+		monitor.SLAThreshold = args.SLAThreshold
+	}
+	if args.Locations != nil {
+		monitor.Locations = append([]string(nil), args.Locations...)
+	}
+	if args.ValidationString != nil {
+		monitor.ValidationString = args.ValidationString
+	}
+	if args.VerifySSL != nil {
+		monitor.VerifySSL = args.VerifySSL
+	}
+	if args.BypassHEADRequest != nil {
+		monitor.BypassHEADRequest = args.BypassHEADRequest
+	}
+	if args.TreatRedirectAsFailure != nil {
+		monitor.TreatRedirectAsFailure = args.TreatRedirectAsFailure
+	}
+
+	return copyMonitor(monitor), nil
+}
+
+// DeleteMonitor deletes a monitor along with its script and labels.
+func (c *Client) DeleteMonitor(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["DeleteMonitor"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.Monitors[id]; !ok {
+		return synthetics.ErrMonitorNotFound
+	}
+	delete(c.Monitors, id)
+	delete(c.Scripts, id)
+	delete(c.Labels, id)
+
+	return nil
+}
+
+// GetMonitorScript returns the script of a monitor.
+func (c *Client) GetMonitorScript(id string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetMonitorScript"]; err != nil {
+		return "", err
+	}
+
+	if _, ok := c.Monitors[id]; !ok {
+		return "", synthetics.ErrMonitorNotFound
+	}
+	script, ok := c.Scripts[id]
+	if !ok {
+		return "", synthetics.ErrMonitorScriptNotFound
+	}
+
+	return script.ScriptText, nil
+}
+
+// UpdateMonitorScript sets the script of a monitor.
+func (c *Client) UpdateMonitorScript(id string, args *synthetics.UpdateMonitorScriptArgs) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateMonitorScript"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.Monitors[id]; !ok {
+		return synthetics.ErrMonitorNotFound
+	}
+	c.Scripts[id] = args
+
+	return nil
+}
+
+// CreateAlertCondition creates an alert condition in a policy.
+func (c *Client) CreateAlertCondition(policyID uint, args *synthetics.CreateAlertConditionArgs) (*synthetics.AlertCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["CreateAlertCondition"]; err != nil {
+		return nil, err
+	}
+
+	condition := &synthetics.AlertCondition{
+		ID:         c.id(),
+		Name:       args.Name,
+		MonitorID:  args.MonitorID,
+		RunbookURL: args.RunbookURL,
+		Enabled:    args.Enabled,
+	}
+	c.AlertConditions[condition.ID] = condition
+	c.AlertConditionPolicies[condition.ID] = policyID
+
+	copied := *condition
+	return &copied, nil
+}
+
+// GetAlertCondition returns an alert condition of a policy.
+func (c *Client) GetAlertCondition(policyID uint, alertConditionID uint) (*synthetics.AlertCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetAlertCondition"]; err != nil {
+		return nil, err
+	}
+
+	condition, ok := c.AlertConditions[alertConditionID]
+	if !ok || c.AlertConditionPolicies[alertConditionID] != policyID {
+		return nil, synthetics.ErrAlertConditionNotFound
+	}
+
+	copied := *condition
+	return &copied, nil
+}
+
+// UpdateAlertCondition replaces an alert condition with the fields it
+// receives, the way New Relic does.
+func (c *Client) UpdateAlertCondition(alertConditionID uint, args *synthetics.UpdateAlertConditionArgs) (*synthetics.AlertCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateAlertCondition"]; err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.AlertConditions[alertConditionID]; !ok {
+		return nil, synthetics.ErrAlertConditionNotFound
+	}
+	condition := &synthetics.AlertCondition{
+		ID:         alertConditionID,
+		Name:       args.Name,
+		MonitorID:  args.MonitorID,
+		RunbookURL: args.RunbookURL,
+		Enabled:    args.Enabled,
+	}
+	c.AlertConditions[alertConditionID] = condition
+
+	copied := *condition
+	return &copied, nil
+}
+
+// DeleteAlertCondition deletes an alert condition.
+func (c *Client) DeleteAlertCondition(alertConditionID uint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["DeleteAlertCondition"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.AlertConditions[alertConditionID]; !ok {
+		return synthetics.ErrAlertConditionNotFound
+	}
+	delete(c.AlertConditions, alertConditionID)
+	delete(c.AlertConditionPolicies, alertConditionID)
+
+	return nil
+}
+
+// GetAlertPolicies returns every alert policy whose name contains
+// name, ordered by ID.
+func (c *Client) GetAlertPolicies(name string) ([]*newrelic.AlertPolicy, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetAlertPolicies"]; err != nil {
+		return nil, err
+	}
+
+	var policies []*newrelic.AlertPolicy
+	for _, policy := range c.Policies {
+		if strings.Contains(policy.Name, name) {
+			copied := *policy
+			policies = append(policies, &copied)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+
+	return policies, nil
+}
+
+// GetSyntheticsConditions returns the alert conditions of a policy,
+// ordered by ID.
+func (c *Client) GetSyntheticsConditions(policyID uint) ([]*newrelic.SyntheticsCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetSyntheticsConditions"]; err != nil {
+		return nil, err
+	}
+
+	var conditions []*newrelic.SyntheticsCondition
+	for id, condition := range c.AlertConditions {
+		if c.AlertConditionPolicies[id] != policyID {
+			continue
+		}
+		conditions = append(conditions, &newrelic.SyntheticsCondition{
+			ID:         condition.ID,
+			Name:       condition.Name,
+			MonitorID:  condition.MonitorID,
+			RunbookURL: condition.RunbookURL,
+			Enabled:    condition.Enabled,
+		})
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+
+	return conditions, nil
+}
+
+// UpdateSyntheticsCondition replaces an alert condition and, unless
+// IgnoreConditionMoves is set, moves it to condition.PolicyID.
+func (c *Client) UpdateSyntheticsCondition(conditionID uint, condition *newrelic.SyntheticsCondition) (*newrelic.SyntheticsCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateSyntheticsCondition"]; err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.AlertConditions[conditionID]; !ok {
+		return nil, newrelic.ErrSyntheticsConditionNotFound
+	}
+	c.AlertConditions[conditionID] = &synthetics.AlertCondition{
+		ID:         conditionID,
+		Name:       condition.Name,
+		MonitorID:  condition.MonitorID,
+		RunbookURL: condition.RunbookURL,
+		Enabled:    condition.Enabled,
+	}
+	if condition.PolicyID != 0 && !c.IgnoreConditionMoves {
+		c.AlertConditionPolicies[conditionID] = condition.PolicyID
+	}
+
+	updated := *condition
+	updated.ID = conditionID
+	updated.PolicyID = 0
+	return &updated, nil
+}
+
+// GetMonitorLabels returns the labels of a monitor.
+func (c *Client) GetMonitorLabels(monitorID string) ([]*newrelic.MonitorLabel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetMonitorLabels"]; err != nil {
+		return nil, err
+	}
+
+	labels := make([]*newrelic.MonitorLabel, len(c.Labels[monitorID]))
+	for i, label := range c.Labels[monitorID] {
+		copied := *label
+		labels[i] = &copied
+	}
+
+	return labels, nil
+}
+
+// AddMonitorLabel adds a label to a monitor.
+func (c *Client) AddMonitorLabel(monitorID string, label *newrelic.MonitorLabel) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["AddMonitorLabel"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.Monitors[monitorID]; !ok {
+		return synthetics.ErrMonitorNotFound
+	}
+	for _, l := range c.Labels[monitorID] {
+		if *l == *label {
+			return nil
+		}
+	}
+	copied := *label
+	c.Labels[monitorID] = append(c.Labels[monitorID], &copied)
+
+	return nil
+}
+
+// DeleteMonitorLabel removes a label from a monitor. Removing a label
+// the monitor doesn't have succeeds.
+func (c *Client) DeleteMonitorLabel(monitorID string, label *newrelic.MonitorLabel) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["DeleteMonitorLabel"]; err != nil {
+		return err
+	}
+
+	labels := c.Labels[monitorID][:0]
+	for _, l := range c.Labels[monitorID] {
+		if *l != *label {
+			labels = append(labels, l)
+		}
+	}
+	c.Labels[monitorID] = labels
+
+	return nil
+}
+
+// copyLocationFailureCondition returns a copy of a multi-location alert
+// condition, so that callers can't change the stored one.
+func copyLocationFailureCondition(condition *newrelic.LocationFailureCondition) *newrelic.LocationFailureCondition {
+	c := *condition
+	c.Entities = append([]string(nil), condition.Entities...)
+	c.Terms = nil
+	for _, term := range condition.Terms {
+		copied := *term
+		c.Terms = append(c.Terms, &copied)
+	}
+	return &c
+}
+
+// GetLocationFailureConditions returns the multi-location alert
+// conditions of a policy, ordered by ID.
+func (c *Client) GetLocationFailureConditions(policyID uint) ([]*newrelic.LocationFailureCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetLocationFailureConditions"]; err != nil {
+		return nil, err
+	}
+
+	var conditions []*newrelic.LocationFailureCondition
+	for id, condition := range c.LocationFailureConditions {
+		if c.LocationFailureConditionPolicies[id] == policyID {
+			conditions = append(conditions, copyLocationFailureCondition(condition))
+		}
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+
+	return conditions, nil
+}
+
+// GetLocationFailureCondition returns a multi-location alert condition
+// of a policy.
+func (c *Client) GetLocationFailureCondition(policyID uint, conditionID uint) (*newrelic.LocationFailureCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetLocationFailureCondition"]; err != nil {
+		return nil, err
+	}
+
+	condition, ok := c.LocationFailureConditions[conditionID]
+	if !ok || c.LocationFailureConditionPolicies[conditionID] != policyID {
+		return nil, newrelic.ErrLocationFailureConditionNotFound
+	}
+
+	return copyLocationFailureCondition(condition), nil
+}
+
+// CreateLocationFailureCondition creates a multi-location alert
+// condition in a policy.
+func (c *Client) CreateLocationFailureCondition(policyID uint, condition *newrelic.LocationFailureCondition) (*newrelic.LocationFailureCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["CreateLocationFailureCondition"]; err != nil {
+		return nil, err
+	}
+
+	created := copyLocationFailureCondition(condition)
+	created.ID = c.id()
+	c.LocationFailureConditions[created.ID] = created
+	c.LocationFailureConditionPolicies[created.ID] = policyID
+
+	return copyLocationFailureCondition(created), nil
+}
+
+// UpdateLocationFailureCondition replaces a multi-location alert
+// condition.
+func (c *Client) UpdateLocationFailureCondition(conditionID uint, condition *newrelic.LocationFailureCondition) (*newrelic.LocationFailureCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateLocationFailureCondition"]; err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.LocationFailureConditions[conditionID]; !ok {
+		return nil, newrelic.ErrLocationFailureConditionNotFound
+	}
+	updated := copyLocationFailureCondition(condition)
+	updated.ID = conditionID
+	c.LocationFailureConditions[conditionID] = updated
+
+	return copyLocationFailureCondition(updated), nil
+}
+
+// DeleteLocationFailureCondition deletes a multi-location alert
+// condition.
+func (c *Client) DeleteLocationFailureCondition(conditionID uint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["DeleteLocationFailureCondition"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.LocationFailureConditions[conditionID]; !ok {
+		return newrelic.ErrLocationFailureConditionNotFound
+	}
+	delete(c.LocationFailureConditions, conditionID)
+	delete(c.LocationFailureConditionPolicies, conditionID)
+
+	return nil
+}
+
+// copyNRQLCondition returns a copy of a NRQL alert condition, so that
+// callers can't change the stored one.
+func copyNRQLCondition(condition *newrelic.NRQLCondition) *newrelic.NRQLCondition {
+	c := *condition
+	c.Terms = nil
+	for _, term := range condition.Terms {
+		copied := *term
+		c.Terms = append(c.Terms, &copied)
+	}
+	return &c
+}
+
+// GetNRQLConditions returns the NRQL alert conditions of a policy,
+// ordered by ID.
+func (c *Client) GetNRQLConditions(policyID uint) ([]*newrelic.NRQLCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetNRQLConditions"]; err != nil {
+		return nil, err
+	}
+
+	var conditions []*newrelic.NRQLCondition
+	for id, condition := range c.NRQLConditions {
+		if c.NRQLConditionPolicies[id] == policyID {
+			conditions = append(conditions, copyNRQLCondition(condition))
+		}
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].ID < conditions[j].ID })
+
+	return conditions, nil
+}
+
+// GetNRQLCondition returns a NRQL alert condition of a policy.
+func (c *Client) GetNRQLCondition(policyID uint, conditionID uint) (*newrelic.NRQLCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetNRQLCondition"]; err != nil {
+		return nil, err
+	}
+
+	condition, ok := c.NRQLConditions[conditionID]
+	if !ok || c.NRQLConditionPolicies[conditionID] != policyID {
+		return nil, newrelic.ErrNRQLConditionNotFound
+	}
+
+	return copyNRQLCondition(condition), nil
+}
+
+// CreateNRQLCondition creates a NRQL alert condition in a policy.
+func (c *Client) CreateNRQLCondition(policyID uint, condition *newrelic.NRQLCondition) (*newrelic.NRQLCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["CreateNRQLCondition"]; err != nil {
+		return nil, err
+	}
+
+	created := copyNRQLCondition(condition)
+	created.ID = c.id()
+	c.NRQLConditions[created.ID] = created
+	c.NRQLConditionPolicies[created.ID] = policyID
+
+	return copyNRQLCondition(created), nil
+}
+
+// UpdateNRQLCondition replaces a NRQL alert condition.
+func (c *Client) UpdateNRQLCondition(conditionID uint, condition *newrelic.NRQLCondition) (*newrelic.NRQLCondition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["UpdateNRQLCondition"]; err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.NRQLConditions[conditionID]; !ok {
+		return nil, newrelic.ErrNRQLConditionNotFound
+	}
+	updated := copyNRQLCondition(condition)
+	updated.ID = conditionID
+	c.NRQLConditions[conditionID] = updated
+
+	return copyNRQLCondition(updated), nil
+}
+
+// DeleteNRQLCondition deletes a NRQL alert condition.
+func (c *Client) DeleteNRQLCondition(conditionID uint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["DeleteNRQLCondition"]; err != nil {
+		return err
+	}
+
+	if _, ok := c.NRQLConditions[conditionID]; !ok {
+		return newrelic.ErrNRQLConditionNotFound
+	}
+	delete(c.NRQLConditions, conditionID)
+	delete(c.NRQLConditionPolicies, conditionID)
+
+	return nil
+}
+
+// GetLocations returns Locations.
+func (c *Client) GetLocations() ([]*newrelic.Location, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["GetLocations"]; err != nil {
+		return nil, err
+	}
+
+	locations := make([]*newrelic.Location, len(c.Locations))
+	for i, location := range c.Locations {
+		copied := *location
+		locations[i] = &copied
+	}
+
+	return locations, nil
+}
+
+// Query records a NRQL query and returns its entry in QueryResults.
+func (c *Client) Query(nrql string) (*newrelic.InsightsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Errors["Query"]; err != nil {
+		return nil, err
+	}
+
+	c.Queries = append(c.Queries, nrql)
+	resp, ok := c.QueryResults[nrql]
+	if !ok {
+		return nil, fmt.Errorf("fake: no result for query %q", nrql)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,170 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type syntheticsCondition struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	MonitorID  string `json:"monitor_id"`
+	RunbookURL string `json:"runbook_url,omitempty"`
+	Enabled    bool   `json:"enabled"`
+
+	policyID uint
+}
+
+type syntheticsConditionBody struct {
+	SyntheticsCondition *syntheticsCondition `json:"synthetics_condition"`
+}
+
+func (a *API) serveAlerts(w http.ResponseWriter, r *http.Request, path string) {
+	parts := pathParts(path)
+
+	switch {
+	case len(parts) == 1 && parts[0] == "alerts_synthetics_conditions.json":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		a.listConditions(w, r)
+	case len(parts) == 3 && parts[0] == "alerts_synthetics_conditions" && parts[1] == "policies":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, r)
+			return
+		}
+		policyID, ok := jsonID(w, parts[2])
+		if !ok {
+			return
+		}
+		a.createCondition(w, r, policyID)
+	case len(parts) == 2 && parts[0] == "alerts_synthetics_conditions":
+		id, ok := jsonID(w, parts[1])
+		if !ok {
+			return
+		}
+		a.serveCondition(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// jsonID parses an ID path segment such as "123.json", responding with
+// 404 if it isn't one.
+func jsonID(w http.ResponseWriter, segment string) (uint, bool) {
+	id, err := strconv.ParseUint(strings.TrimSuffix(segment, ".json"), 10, 64)
+	if err != nil || !strings.HasSuffix(segment, ".json") {
+		writeError(w, http.StatusNotFound, "Not found")
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// listConditions returns a page of the conditions of a policy. Like New
+// Relic, it sets a Link header when there are more pages.
+func (a *API) listConditions(w http.ResponseWriter, r *http.Request) {
+	policyID, err := strconv.ParseUint(r.URL.Query().Get("policy_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "policy_id is required")
+		return
+	}
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			writeError(w, http.StatusUnprocessableEntity, "page must be a positive integer")
+			return
+		}
+	}
+
+	var matches []*syntheticsCondition
+	for _, id := range a.conditionList {
+		if condition := a.conditions[id]; condition.policyID == uint(policyID) {
+			matches = append(matches, condition)
+		}
+	}
+
+	conditions := []*syntheticsCondition{}
+	start := (page - 1) * a.AlertsPageSize
+	for i := start; i < len(matches) && i < start+a.AlertsPageSize; i++ {
+		conditions = append(conditions, matches[i])
+	}
+	if start+a.AlertsPageSize < len(matches) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s/alerts_synthetics_conditions.json?policy_id=%d&page=%d>; rel="next"`, "http://"+r.Host, AlertsPath, policyID, page+1))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"synthetics_conditions": conditions})
+}
+
+// readCondition reads and validates a condition from a request,
+// responding with 422 if it isn't valid.
+func (a *API) readCondition(w http.ResponseWriter, r *http.Request) (*syntheticsCondition, bool) {
+	var body syntheticsConditionBody
+	if !readJSON(w, r, &body) {
+		return nil, false
+	}
+
+	condition := body.SyntheticsCondition
+	switch {
+	case condition == nil:
+		writeError(w, http.StatusUnprocessableEntity, "synthetics_condition is required")
+	case condition.Name == "":
+		writeError(w, http.StatusUnprocessableEntity, "Name can't be blank")
+	case condition.MonitorID == "":
+		writeError(w, http.StatusUnprocessableEntity, "Monitor can't be blank")
+	case a.monitors[condition.MonitorID] == nil:
+		writeError(w, http.StatusUnprocessableEntity, "Monitor %s does not exist", condition.MonitorID)
+	default:
+		return condition, true
+	}
+
+	return nil, false
+}
+
+func (a *API) createCondition(w http.ResponseWriter, r *http.Request, policyID uint) {
+	condition, ok := a.readCondition(w, r)
+	if !ok {
+		return
+	}
+
+	condition.ID = a.id()
+	condition.policyID = policyID
+	a.conditions[condition.ID] = condition
+	a.conditionList = append(a.conditionList, condition.ID)
+
+	writeJSON(w, http.StatusCreated, syntheticsConditionBody{SyntheticsCondition: condition})
+}
+
+func (a *API) serveCondition(w http.ResponseWriter, r *http.Request, id uint) {
+	existing, ok := a.conditions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Synthetics condition %d not found", id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		condition, ok := a.readCondition(w, r)
+		if !ok {
+			return
+		}
+		condition.ID = id
+		condition.policyID = existing.policyID
+		a.conditions[id] = condition
+		writeJSON(w, http.StatusOK, syntheticsConditionBody{SyntheticsCondition: condition})
+	case http.MethodDelete:
+		delete(a.conditions, id)
+		for i, conditionID := range a.conditionList {
+			if conditionID == id {
+				a.conditionList = append(a.conditionList[:i], a.conditionList[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, http.StatusOK, syntheticsConditionBody{SyntheticsCondition: existing})
+	default:
+		methodNotAllowed(w, r)
+	}
+}
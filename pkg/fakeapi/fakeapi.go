@@ -0,0 +1,140 @@
+// Package fakeapi emulates the parts of the New Relic Synthetics and
+// Alerts REST APIs the provider uses, for end-to-end tests that run
+// without New Relic credentials.
+//
+// Synthetics endpoints are served under SyntheticsPath and Alerts
+// endpoints under AlertsPath, so a provider pointed at
+//
+//	synthetics_base_url = "<server>/synthetics/api"
+//	alerts_base_url     = "<server>/v2"
+//
+// talks to the emulator instead of New Relic.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	// SyntheticsPath is the path the Synthetics API is served under.
+	SyntheticsPath = "/synthetics/api"
+	// AlertsPath is the path the Alerts API is served under.
+	AlertsPath = "/v2"
+
+	// maxMonitorsLimit is the largest page of monitors New Relic
+	// returns.
+	maxMonitorsLimit = 100
+	// defaultAlertsPageSize is the number of alert conditions New
+	// Relic returns per page.
+	defaultAlertsPageSize = 50
+)
+
+// API is an in-memory emulation of the New Relic Synthetics and Alerts
+// REST APIs. It is safe for concurrent use.
+type API struct {
+	mu     sync.Mutex
+	nextID uint
+
+	apiKey string
+
+	monitors      map[string]*monitor
+	monitorOrder  []string
+	scripts       map[string]*script
+	labels        map[string][]*label
+	conditions    map[uint]*syntheticsCondition
+	conditionList []uint
+
+	// AlertsPageSize is the number of alert conditions returned per
+	// page.
+	AlertsPageSize int
+}
+
+// New returns an empty API that accepts requests with apiKey in their
+// X-Api-Key header. An empty apiKey accepts any key.
+func New(apiKey string) *API {
+	return &API{
+		nextID:         1000,
+		apiKey:         apiKey,
+		monitors:       map[string]*monitor{},
+		scripts:        map[string]*script{},
+		labels:         map[string][]*label{},
+		conditions:     map[uint]*syntheticsCondition{},
+		AlertsPageSize: defaultAlertsPageSize,
+	}
+}
+
+// NewServer starts an httptest.Server serving a new API. The caller
+// must close the server.
+func NewServer(apiKey string) (*API, *httptest.Server) {
+	api := New(apiKey)
+	return api, httptest.NewServer(api)
+}
+
+func (a *API) id() uint {
+	a.nextID++
+	return a.nextID
+}
+
+// ServeHTTP routes a request to the Synthetics or Alerts API.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if key := r.Header.Get("X-Api-Key"); key == "" || (a.apiKey != "" && key != a.apiKey) {
+		writeError(w, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, SyntheticsPath+"/"):
+		a.serveSynthetics(w, r, strings.TrimPrefix(r.URL.Path, SyntheticsPath))
+	case strings.HasPrefix(r.URL.Path, AlertsPath+"/"):
+		a.serveAlerts(w, r, strings.TrimPrefix(r.URL.Path, AlertsPath))
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// pathParts splits a path into its segments.
+func pathParts(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// errorBody is the body of an error response.
+type errorBody struct {
+	Error struct {
+		Title string `json:"title"`
+	} `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	var body errorBody
+	body.Error.Title = fmt.Sprintf(format, args...)
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// readJSON decodes a request body, responding with 400 if it isn't
+// valid JSON.
+func readJSON(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON: %s", err)
+		return false
+	}
+
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, "Method %s not allowed", r.Method)
+}
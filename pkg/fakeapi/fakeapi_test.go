@@ -0,0 +1,192 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func request(t *testing.T, method, url string, body interface{}, out interface{}) *http.Response {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("X-Api-Key", "key")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	return resp
+}
+
+func TestMonitors(t *testing.T) {
+	_, server := NewServer("key")
+	defer server.Close()
+	base := server.URL + SyntheticsPath + "/v3/monitors"
+
+	resp := request(t, http.MethodPost, base, map[string]interface{}{
+		"name": "monitor", "type": "SIMPLE", "frequency": 3, "locations": []string{}, "status": "ON",
+	}, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected an invalid monitor to be rejected with 400, got %d", resp.StatusCode)
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp := request(t, http.MethodPost, base, map[string]interface{}{
+			"name": fmt.Sprintf("monitor-%d", i), "type": "SCRIPT_API", "frequency": 5,
+			"locations": []string{"AWS_US_WEST_1"}, "status": "ENABLED",
+		}, nil)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+		location := resp.Header.Get("Location")
+		ids = append(ids, location[strings.LastIndex(location, "/")+1:])
+	}
+
+	var page struct {
+		Monitors []*monitor `json:"monitors"`
+		Count    int        `json:"count"`
+	}
+	request(t, http.MethodGet, base+"?offset=2&limit=2", nil, &page)
+	if page.Count != 3 || len(page.Monitors) != 1 || page.Monitors[0].ID != ids[2] {
+		t.Errorf("expected the last monitor of 3, got %d of %d", len(page.Monitors), page.Count)
+	}
+	if resp := request(t, http.MethodGet, base+"?limit=101", nil, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a limit over 100 to be rejected with 400, got %d", resp.StatusCode)
+	}
+
+	resp = request(t, http.MethodPatch, base+"/"+ids[0], map[string]interface{}{"frequency": 15}, nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+	var m monitor
+	request(t, http.MethodGet, base+"/"+ids[0], nil, &m)
+	if m.Frequency != 15 || m.Name != "monitor-0" {
+		t.Errorf("expected only the frequency to change, got %#v", m)
+	}
+
+	if resp := request(t, http.MethodGet, base+"/"+ids[0]+"/script", nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a missing script to be a 404, got %d", resp.StatusCode)
+	}
+	text := base64.StdEncoding.EncodeToString([]byte("console.log('check')"))
+	if resp := request(t, http.MethodPut, base+"/"+ids[0]+"/script", map[string]string{"scriptText": text}, nil); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+	var s script
+	request(t, http.MethodGet, base+"/"+ids[0]+"/script", nil, &s)
+	if s.ScriptText != text {
+		t.Errorf("expected the script back, got %q", s.ScriptText)
+	}
+
+	if resp := request(t, http.MethodDelete, base+"/"+ids[0], nil, nil); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodGet, base+"/"+ids[0], nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a deleted monitor to be a 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, server := NewServer("key")
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + SyntheticsPath + "/v3/monitors")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a request without an API key to be a 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestSyntheticsConditionsAndLabels(t *testing.T) {
+	_, server := NewServer("key")
+	defer server.Close()
+
+	resp := request(t, http.MethodPost, server.URL+SyntheticsPath+"/v3/monitors", map[string]interface{}{
+		"name": "monitor", "type": "SIMPLE", "frequency": 5, "uri": "https://example.com",
+		"locations": []string{"AWS_US_WEST_1"}, "status": "ENABLED",
+	}, nil)
+	location := resp.Header.Get("Location")
+	monitorID := location[strings.LastIndex(location, "/")+1:]
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.SyntheticsBaseURL = server.URL + SyntheticsPath
+		c.AlertsBaseURL = server.URL + AlertsPath
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := client.AddMonitorLabel(monitorID, &newrelic.MonitorLabel{Category: "Team", Label: "web"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	labels, err := client.GetMonitorLabels(monitorID)
+	if err != nil || len(labels) != 1 {
+		t.Fatalf("expected 1 label, got %v (%v)", labels, err)
+	}
+	if err := client.DeleteMonitorLabel(monitorID, labels[0]); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	base := server.URL + AlertsPath + "/alerts_synthetics_conditions"
+	resp = request(t, http.MethodPost, base+"/policies/1.json", map[string]interface{}{
+		"synthetics_condition": map[string]interface{}{"name": "condition", "monitor_id": "missing"},
+	}, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected a condition for a missing monitor to be a 422, got %d", resp.StatusCode)
+	}
+
+	var created syntheticsConditionBody
+	resp = request(t, http.MethodPost, base+"/policies/1.json", map[string]interface{}{
+		"synthetics_condition": map[string]interface{}{"name": "condition", "monitor_id": monitorID, "enabled": true},
+	}, &created)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	conditions, err := client.GetSyntheticsConditions(1)
+	if err != nil || len(conditions) != 1 || conditions[0].ID != created.SyntheticsCondition.ID {
+		t.Fatalf("expected the created condition, got %v (%v)", conditions, err)
+	}
+	if conditions, _ := client.GetSyntheticsConditions(2); len(conditions) != 0 {
+		t.Errorf("expected no conditions in another policy, got %v", conditions)
+	}
+
+	url := fmt.Sprintf("%s/%d.json", base, created.SyntheticsCondition.ID)
+	if resp := request(t, http.MethodDelete, url, nil, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp := request(t, http.MethodDelete, url, nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected deleting a deleted condition to be a 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,429 @@
+package fakeapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	monitorTypes       = []string{"SIMPLE", "BROWSER", "SCRIPT_API", "SCRIPT_BROWSER"}
+	monitorFrequencies = []uint{1, 5, 10, 15, 30, 60, 360, 720, 1440}
+	monitorStatuses    = []string{"ENABLED", "MUTED", "DISABLED"}
+)
+
+type monitorOptions struct {
+	ValidationString       *string `json:"validationString,omitempty"`
+	VerifySSL              *bool   `json:"verifySSL,omitempty"`
+	BypassHEADRequest      *bool   `json:"bypassHEADRequest,omitempty"`
+	TreatRedirectAsFailure *bool   `json:"treatRedirectAsFailure,omitempty"`
+}
+
+type monitor struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	Frequency    uint           `json:"frequency"`
+	URI          string         `json:"uri,omitempty"`
+	Locations    []string       `json:"locations"`
+	Status       string         `json:"status"`
+	SLAThreshold float64        `json:"slaThreshold"`
+	Options      monitorOptions `json:"options"`
+	UserID       uint           `json:"userId"`
+	APIVersion   string         `json:"apiVersion"`
+	CreatedAt    string         `json:"createdAt"`
+	ModifiedAt   string         `json:"modifiedAt"`
+}
+
+// monitorPatch is a partial monitor; unset fields are left alone.
+type monitorPatch struct {
+	Name         *string         `json:"name"`
+	Type         *string         `json:"type"`
+	Frequency    *uint           `json:"frequency"`
+	URI          *string         `json:"uri"`
+	Locations    []string        `json:"locations"`
+	Status       *string         `json:"status"`
+	SLAThreshold *float64        `json:"slaThreshold"`
+	Options      *monitorOptions `json:"options"`
+}
+
+type scriptLocation struct {
+	Name string `json:"name"`
+	HMAC string `json:"hmac,omitempty"`
+}
+
+type script struct {
+	ScriptText      string           `json:"scriptText"`
+	ScriptLocations []scriptLocation `json:"scriptLocations,omitempty"`
+}
+
+type label struct {
+	Category string `json:"category"`
+	Label    string `json:"label"`
+}
+
+type validationErrorsBody struct {
+	Errors []validationError `json:"errors"`
+}
+
+type validationError struct {
+	Error string `json:"error"`
+}
+
+func writeValidationErrors(w http.ResponseWriter, errs []string) {
+	body := validationErrorsBody{}
+	for _, err := range errs {
+		body.Errors = append(body.Errors, validationError{Error: err})
+	}
+	writeJSON(w, http.StatusBadRequest, body)
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000+0000")
+}
+
+// monitorID returns a monitor ID shaped like New Relic's UUIDs.
+func (a *API) monitorID() string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", a.id(), a.nextID)
+}
+
+func (a *API) serveSynthetics(w http.ResponseWriter, r *http.Request, path string) {
+	parts := pathParts(path)
+
+	switch {
+	case len(parts) == 2 && parts[0] == "v1" && parts[1] == "locations":
+		a.serveLocations(w, r)
+	case len(parts) == 2 && parts[0] == "v3" && parts[1] == "monitors":
+		a.serveMonitors(w, r)
+	case len(parts) == 3 && parts[0] == "v3" && parts[1] == "monitors":
+		a.serveMonitor(w, r, parts[2])
+	case len(parts) == 4 && parts[0] == "v3" && parts[1] == "monitors" && parts[3] == "script":
+		a.serveScript(w, r, parts[2])
+	case len(parts) >= 4 && len(parts) <= 5 && parts[0] == "v4" && parts[1] == "monitors" && parts[3] == "labels":
+		var l string
+		if len(parts) == 5 {
+			l = parts[4]
+		}
+		a.serveLabels(w, r, parts[2], l)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (a *API) serveLocations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []map[string]interface{}{
+		{"name": "AWS_US_WEST_1", "label": "San Francisco, CA, USA", "private": false},
+		{"name": "AWS_US_EAST_1", "label": "Washington, DC, USA", "private": false},
+		{"name": "AWS_EU_WEST_1", "label": "Dublin, IE", "private": false},
+		{"name": "AWS_AP_NORTHEAST_1", "label": "Tokyo, JP", "private": false},
+	})
+}
+
+func (a *API) serveMonitors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.listMonitors(w, r)
+	case http.MethodPost:
+		a.createMonitor(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// listMonitors returns a page of monitors, in creation order.
+func (a *API) listMonitors(w http.ResponseWriter, r *http.Request) {
+	offset, limit := 0, 20
+	var err error
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			writeValidationErrors(w, []string{"offset must be a non-negative integer"})
+			return
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxMonitorsLimit {
+			writeValidationErrors(w, []string{fmt.Sprintf("limit must be between 1 and %d", maxMonitorsLimit)})
+			return
+		}
+	}
+
+	monitors := []*monitor{}
+	for i := offset; i < len(a.monitorOrder) && i < offset+limit; i++ {
+		monitors = append(monitors, a.monitors[a.monitorOrder[i]])
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"monitors": monitors,
+		"count":    len(a.monitorOrder),
+	})
+}
+
+// validateMonitor returns what's wrong with a monitor, the way New
+// Relic reports it.
+func validateMonitor(m *monitor) []string {
+	var errs []string
+	if m.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if !containsString(monitorTypes, m.Type) {
+		errs = append(errs, fmt.Sprintf("type must be one of %s", strings.Join(monitorTypes, ", ")))
+	}
+	if !containsUint(monitorFrequencies, m.Frequency) {
+		errs = append(errs, "frequency must be one of 1, 5, 10, 15, 30, 60, 360, 720, 1440")
+	}
+	if len(m.Locations) == 0 {
+		errs = append(errs, "at least one location is required")
+	}
+	if !containsString(monitorStatuses, m.Status) {
+		errs = append(errs, fmt.Sprintf("status must be one of %s", strings.Join(monitorStatuses, ", ")))
+	}
+	if (m.Type == "SIMPLE" || m.Type == "BROWSER") && m.URI == "" {
+		errs = append(errs, "uri is required for SIMPLE and BROWSER monitors")
+	}
+	if m.URI != "" {
+		if u, err := url.Parse(m.URI); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, "uri must be an absolute URL")
+		}
+	}
+
+	return errs
+}
+
+func (a *API) createMonitor(w http.ResponseWriter, r *http.Request) {
+	var m monitor
+	if !readJSON(w, r, &m) {
+		return
+	}
+	if errs := validateMonitor(&m); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	m.ID = a.monitorID()
+	if m.SLAThreshold == 0 {
+		m.SLAThreshold = 7
+	}
+	m.APIVersion = "LATEST"
+	m.CreatedAt = now()
+	m.ModifiedAt = m.CreatedAt
+	a.monitors[m.ID] = &m
+	a.monitorOrder = append(a.monitorOrder, m.ID)
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s://%s%s/v3/monitors/%s", scheme, r.Host, SyntheticsPath, m.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (a *API) serveMonitor(w http.ResponseWriter, r *http.Request, id string) {
+	m, ok := a.monitors[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Monitor %s not found", id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, m)
+	case http.MethodPut:
+		var updated monitor
+		if !readJSON(w, r, &updated) {
+			return
+		}
+		a.updateMonitor(w, m, &updated)
+	case http.MethodPatch:
+		var patch monitorPatch
+		if !readJSON(w, r, &patch) {
+			return
+		}
+		updated := *m
+		patch.apply(&updated)
+		a.updateMonitor(w, m, &updated)
+	case http.MethodDelete:
+		delete(a.monitors, id)
+		delete(a.scripts, id)
+		delete(a.labels, id)
+		for i, monitorID := range a.monitorOrder {
+			if monitorID == id {
+				a.monitorOrder = append(a.monitorOrder[:i], a.monitorOrder[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// updateMonitor replaces a monitor with an updated copy, if it is
+// valid. A monitor's type can't change.
+func (a *API) updateMonitor(w http.ResponseWriter, m, updated *monitor) {
+	if updated.Type == "" {
+		updated.Type = m.Type
+	}
+	errs := validateMonitor(updated)
+	if updated.Type != m.Type {
+		errs = append(errs, "type can't be changed")
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	updated.ID = m.ID
+	updated.APIVersion = m.APIVersion
+	updated.CreatedAt = m.CreatedAt
+	updated.ModifiedAt = now()
+	if updated.SLAThreshold == 0 {
+		updated.SLAThreshold = m.SLAThreshold
+	}
+	*m = *updated
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *monitorPatch) apply(m *monitor) {
+	if p.Name != nil {
+		m.Name = *p.Name
+	}
+	if p.Type != nil {
+		m.Type = *p.Type
+	}
+	if p.Frequency != nil {
+		m.Frequency = *p.Frequency
+	}
+	if p.URI != nil {
+		m.URI = *p.URI
+	}
+	if p.Locations != nil {
+		m.Locations = p.Locations
+	}
+	if p.Status != nil {
+		m.Status = *p.Status
+	}
+	if p.SLAThreshold != nil {
+		m.SLAThreshold = *p.SLAThreshold
+	}
+	if p.Options != nil {
+		if p.Options.ValidationString != nil {
+			m.Options.ValidationString = p.Options.ValidationString
+		}
+		if p.Options.VerifySSL != nil {
+			m.Options.VerifySSL = p.Options.VerifySSL
+		}
+		if p.Options.BypassHEADRequest != nil {
+			m.Options.BypassHEADRequest = p.Options.BypassHEADRequest
+		}
+		if p.Options.TreatRedirectAsFailure != nil {
+			m.Options.TreatRedirectAsFailure = p.Options.TreatRedirectAsFailure
+		}
+	}
+}
+
+func (a *API) serveScript(w http.ResponseWriter, r *http.Request, id string) {
+	m, ok := a.monitors[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Monitor %s not found", id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s, ok := a.scripts[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Monitor %s has no script", id)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"scriptText": s.ScriptText})
+	case http.MethodPut:
+		if m.Type != "SCRIPT_API" && m.Type != "SCRIPT_BROWSER" {
+			writeValidationErrors(w, []string{fmt.Sprintf("%s monitors don't have scripts", m.Type)})
+			return
+		}
+		var s script
+		if !readJSON(w, r, &s) {
+			return
+		}
+		if _, err := base64.StdEncoding.DecodeString(s.ScriptText); err != nil {
+			writeValidationErrors(w, []string{"scriptText must be base64 encoded"})
+			return
+		}
+		a.scripts[id] = &s
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (a *API) serveLabels(w http.ResponseWriter, r *http.Request, id, categoryLabel string) {
+	if _, ok := a.monitors[id]; !ok {
+		writeError(w, http.StatusNotFound, "Monitor %s not found", id)
+		return
+	}
+
+	switch {
+	case categoryLabel == "" && r.Method == http.MethodGet:
+		labels := a.labels[id]
+		if labels == nil {
+			labels = []*label{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"labels": labels})
+	case categoryLabel == "" && r.Method == http.MethodPost:
+		var l label
+		if !readJSON(w, r, &l) {
+			return
+		}
+		if l.Category == "" || l.Label == "" {
+			writeValidationErrors(w, []string{"category and label are required"})
+			return
+		}
+		for _, existing := range a.labels[id] {
+			if *existing == l {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		a.labels[id] = append(a.labels[id], &l)
+		w.WriteHeader(http.StatusNoContent)
+	case categoryLabel != "" && r.Method == http.MethodDelete:
+		for i, existing := range a.labels[id] {
+			if existing.Category+":"+existing.Label == categoryLabel {
+				a.labels[id] = append(a.labels[id][:i], a.labels[id][i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Label %s not found", categoryLabel)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint(values []uint, value uint) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+// Package newrelic is a small client for the parts of the New Relic
+// REST API that new-relic-synthetics-go does not cover.
+package newrelic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultAlertsBaseURL is the base URL of the New Relic Alerts
+	// REST API.
+	DefaultAlertsBaseURL = "https://api.newrelic.com/v2"
+	// DefaultSyntheticsBaseURL is the base URL of the New Relic
+	// Synthetics REST API.
+	DefaultSyntheticsBaseURL = "https://synthetics.newrelic.com/synthetics/api"
+	// DefaultInsightsBaseURL is the base URL of the New Relic Insights
+	// query API.
+	DefaultInsightsBaseURL = "https://insights-api.newrelic.com/v1"
+)
+
+// Client is a client to the New Relic REST API.
+type Client struct {
+	APIKey            string
+	AlertsBaseURL     string
+	SyntheticsBaseURL string
+	HTTPClient        *http.Client
+
+	// AccountID and InsightsQueryKey are only needed for Insights
+	// queries.
+	AccountID        uint
+	InsightsQueryKey string
+	InsightsBaseURL  string
+}
+
+// NewClient instantiates a new Client. Configuration functions are
+// applied in order after the defaults are set.
+func NewClient(configs ...func(*Client)) (*Client, error) {
+	client := &Client{
+		AlertsBaseURL:     DefaultAlertsBaseURL,
+		SyntheticsBaseURL: DefaultSyntheticsBaseURL,
+		InsightsBaseURL:   DefaultInsightsBaseURL,
+		HTTPClient:        http.DefaultClient,
+	}
+	for _, config := range configs {
+		config(client)
+	}
+
+	if client.APIKey == "" {
+		return nil, errors.New("error: an API key is required")
+	}
+
+	return client, nil
+}
+
+// APIError is returned when New Relic responds with an unexpected
+// status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("newrelic: unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// do sends a request with a JSON body (if reqBody is non-nil) and
+// decodes a JSON response into respBody (if respBody is non-nil).
+func (c *Client) do(method, url string, reqBody interface{}, respBody interface{}) error {
+	req, err := c.newRequest(method, url, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.send(req, respBody)
+}
+
+// newRequest builds a request with a JSON body (if reqBody is non-nil).
+func (c *Client) newRequest(method, url string, reqBody interface{}) (*http.Request, error) {
+	var body io.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, errors.Wrap(err, "error: could not encode request")
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error: could not build request")
+	}
+	req.Header.Set("X-Api-Key", c.APIKey)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// send sends a request and decodes a JSON response into respBody (if
+// respBody is non-nil).
+func (c *Client) send(req *http.Request, respBody interface{}) error {
+	_, data, err := c.roundTrip(req)
+	if err != nil {
+		return err
+	}
+
+	if respBody != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, respBody); err != nil {
+			return errors.Wrap(err, "error: could not decode response")
+		}
+	}
+
+	return nil
+}
+
+// roundTrip sends a request and returns the response along with its
+// body, which has been read and closed.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error: could not send request")
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error: could not read response")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, &APIError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	return resp, data, nil
+}
+
+// getPages GETs url and then every page that follows it, as given by
+// the rel="next" link of each response's Link header. The JSON body of
+// each page is decoded into a new value from newBody and passed to add.
+func (c *Client) getPages(url string, newBody func() interface{}, add func(body interface{})) error {
+	for url != "" {
+		req, err := c.newRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, data, err := c.roundTrip(req)
+		if err != nil {
+			return err
+		}
+
+		body := newBody()
+		if err := json.Unmarshal(data, body); err != nil {
+			return errors.Wrap(err, "error: could not decode response")
+		}
+		add(body)
+
+		url, err = nextPage(req.URL, resp.Header.Get("Link"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextPage returns the rel="next" URL of a Link header, resolved
+// against the URL of the request, or "" if there isn't one.
+func nextPage(base *url.URL, link string) (string, error) {
+	for _, value := range strings.Split(link, ",") {
+		parts := strings.Split(value, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			if strings.Replace(strings.TrimSpace(param), " ", "", -1) != `rel="next"` {
+				continue
+			}
+			next, err := base.Parse(target[1 : len(target)-1])
+			if err != nil {
+				return "", errors.Wrapf(err, "error: invalid next page link %q", link)
+			}
+			return next.String(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isNotFound returns whether err is a 404 from New Relic.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsRefused returns whether err is New Relic rejecting a request as
+// invalid, as opposed to failing to handle it.
+func IsRefused(err error) bool {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusUnprocessableEntity:
+		return true
+	}
+
+	return false
+}
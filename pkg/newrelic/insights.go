@@ -0,0 +1,44 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// InsightsFacet is the result of a NRQL query for one facet value.
+type InsightsFacet struct {
+	Name    string                   `json:"name"`
+	Results []map[string]interface{} `json:"results"`
+}
+
+// InsightsResponse is the result of a NRQL query. Results holds one
+// entry per selected function, in order, keyed by the function name.
+type InsightsResponse struct {
+	Results []map[string]interface{} `json:"results"`
+	Facets  []*InsightsFacet         `json:"facets"`
+}
+
+// Query runs a NRQL query against Insights.
+func (c *Client) Query(nrql string) (*InsightsResponse, error) {
+	if c.AccountID == 0 || c.InsightsQueryKey == "" {
+		return nil, errors.New("error: an account ID and an Insights query key are required to run NRQL queries")
+	}
+
+	u := fmt.Sprintf("%s/accounts/%d/query?nrql=%s", c.InsightsBaseURL, c.AccountID, url.QueryEscape(nrql))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error: could not build request")
+	}
+	req.Header.Set("X-Query-Key", c.InsightsQueryKey)
+	req.Header.Set("Accept", "application/json")
+
+	var resp InsightsResponse
+	if err := c.send(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
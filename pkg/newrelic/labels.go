@@ -0,0 +1,52 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// MonitorLabel is a category:label pair attached to a Synthetics
+// monitor.
+type MonitorLabel struct {
+	Category string `json:"category"`
+	Label    string `json:"label"`
+}
+
+type monitorLabelsBody struct {
+	Labels []*MonitorLabel `json:"labels"`
+}
+
+// GetMonitorLabels returns the labels attached to a monitor.
+func (c *Client) GetMonitorLabels(monitorID string) ([]*MonitorLabel, error) {
+	u := fmt.Sprintf("%s/v4/monitors/%s/labels", c.SyntheticsBaseURL, url.PathEscape(monitorID))
+
+	var body monitorLabelsBody
+	if err := c.do(http.MethodGet, u, nil, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Labels, nil
+}
+
+// AddMonitorLabel attaches a label to a monitor.
+func (c *Client) AddMonitorLabel(monitorID string, label *MonitorLabel) error {
+	u := fmt.Sprintf("%s/v4/monitors/%s/labels", c.SyntheticsBaseURL, url.PathEscape(monitorID))
+
+	return c.do(http.MethodPost, u, label, nil)
+}
+
+// DeleteMonitorLabel detaches a label from a monitor.
+func (c *Client) DeleteMonitorLabel(monitorID string, label *MonitorLabel) error {
+	u := fmt.Sprintf(
+		"%s/v4/monitors/%s/labels/%s",
+		c.SyntheticsBaseURL, url.PathEscape(monitorID), url.PathEscape(label.Category+":"+label.Label),
+	)
+
+	err := c.do(http.MethodDelete, u, nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,125 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLocationFailureConditionNotFound is returned when a
+// multi-location alert condition cannot be found.
+var ErrLocationFailureConditionNotFound = errors.New("newrelic: location failure condition not found")
+
+const (
+	// PriorityCritical is the priority of a critical term.
+	PriorityCritical = "critical"
+	// PriorityWarning is the priority of a warning term.
+	PriorityWarning = "warning"
+)
+
+// LocationFailureTerm is a threshold, expressed as a number of failing
+// locations, at which a violation of the given priority is opened.
+type LocationFailureTerm struct {
+	Priority  string `json:"priority"`
+	Threshold int    `json:"threshold"`
+}
+
+// LocationFailureCondition is a multi-location Synthetics alert
+// condition.
+type LocationFailureCondition struct {
+	ID                        uint                   `json:"id,omitempty"`
+	Name                      string                 `json:"name"`
+	Enabled                   bool                   `json:"enabled"`
+	Entities                  []string               `json:"entities"`
+	Terms                     []*LocationFailureTerm `json:"terms"`
+	ViolationTimeLimitSeconds int                    `json:"violation_time_limit_seconds"`
+	RunbookURL                string                 `json:"runbook_url,omitempty"`
+}
+
+type locationFailureConditionBody struct {
+	LocationFailureCondition *LocationFailureCondition `json:"location_failure_condition"`
+}
+
+type locationFailureConditionsBody struct {
+	LocationFailureConditions []*LocationFailureCondition `json:"location_failure_conditions"`
+}
+
+// GetLocationFailureConditions returns every multi-location alert
+// condition attached to a policy, from every page.
+func (c *Client) GetLocationFailureConditions(policyID uint) ([]*LocationFailureCondition, error) {
+	url := fmt.Sprintf("%s/alerts_location_failure_conditions/policies/%d.json", c.AlertsBaseURL, policyID)
+
+	var conditions []*LocationFailureCondition
+	err := c.getPages(url, func() interface{} { return &locationFailureConditionsBody{} }, func(body interface{}) {
+		conditions = append(conditions, body.(*locationFailureConditionsBody).LocationFailureConditions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
+
+// GetLocationFailureCondition returns a multi-location alert condition
+// by policy and condition ID.
+func (c *Client) GetLocationFailureCondition(policyID uint, conditionID uint) (*LocationFailureCondition, error) {
+	conditions, err := c.GetLocationFailureConditions(policyID)
+	if isNotFound(err) {
+		return nil, ErrLocationFailureConditionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range conditions {
+		if condition.ID == conditionID {
+			return condition, nil
+		}
+	}
+
+	return nil, ErrLocationFailureConditionNotFound
+}
+
+// CreateLocationFailureCondition creates a multi-location alert
+// condition on a policy.
+func (c *Client) CreateLocationFailureCondition(policyID uint, condition *LocationFailureCondition) (*LocationFailureCondition, error) {
+	url := fmt.Sprintf("%s/alerts_location_failure_conditions/policies/%d.json", c.AlertsBaseURL, policyID)
+
+	var body locationFailureConditionBody
+	if err := c.do(http.MethodPost, url, &locationFailureConditionBody{condition}, &body); err != nil {
+		return nil, err
+	}
+
+	return body.LocationFailureCondition, nil
+}
+
+// UpdateLocationFailureCondition replaces a multi-location alert
+// condition.
+func (c *Client) UpdateLocationFailureCondition(conditionID uint, condition *LocationFailureCondition) (*LocationFailureCondition, error) {
+	url := fmt.Sprintf("%s/alerts_location_failure_conditions/%d.json", c.AlertsBaseURL, conditionID)
+
+	var body locationFailureConditionBody
+	err := c.do(http.MethodPut, url, &locationFailureConditionBody{condition}, &body)
+	if isNotFound(err) {
+		return nil, ErrLocationFailureConditionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return body.LocationFailureCondition, nil
+}
+
+// DeleteLocationFailureCondition deletes a multi-location alert
+// condition.
+func (c *Client) DeleteLocationFailureCondition(conditionID uint) error {
+	url := fmt.Sprintf("%s/alerts_location_failure_conditions/%d.json", c.AlertsBaseURL, conditionID)
+
+	err := c.do(http.MethodDelete, url, nil, nil)
+	if isNotFound(err) {
+		return ErrLocationFailureConditionNotFound
+	}
+
+	return err
+}
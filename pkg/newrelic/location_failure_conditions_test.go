@@ -0,0 +1,142 @@
+package newrelic_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestLocationFailureConditions(t *testing.T) {
+	conditions := map[uint]*newrelic.LocationFailureCondition{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alerts_location_failure_conditions/policies/42.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			var list []*newrelic.LocationFailureCondition
+			for _, condition := range conditions {
+				list = append(list, condition)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"location_failure_conditions": list})
+		case http.MethodPost:
+			var body map[string]*newrelic.LocationFailureCondition
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			condition := body["location_failure_condition"]
+			condition.ID = 7
+			conditions[condition.ID] = condition
+			json.NewEncoder(w).Encode(body)
+		}
+	})
+	mux.HandleFunc("/alerts_location_failure_conditions/7.json", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := conditions[7]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(conditions, 7)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	created, err := client.CreateLocationFailureCondition(42, &newrelic.LocationFailureCondition{
+		Name:     "condition",
+		Enabled:  true,
+		Entities: []string{"monitor"},
+		Terms: []*newrelic.LocationFailureTerm{
+			{Priority: newrelic.PriorityCritical, Threshold: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if created.ID != 7 {
+		t.Fatalf("expected ID 7, got %d", created.ID)
+	}
+
+	condition, err := client.GetLocationFailureCondition(42, 7)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if condition.Name != "condition" || condition.Terms[0].Threshold != 2 {
+		t.Fatalf("unexpected condition: %+v", condition)
+	}
+
+	if err := client.DeleteLocationFailureCondition(7); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := client.GetLocationFailureCondition(42, 7); err != newrelic.ErrLocationFailureConditionNotFound {
+		t.Fatalf("expected ErrLocationFailureConditionNotFound, got %v", err)
+	}
+	if err := client.DeleteLocationFailureCondition(7); err != newrelic.ErrLocationFailureConditionNotFound {
+		t.Fatalf("expected ErrLocationFailureConditionNotFound, got %v", err)
+	}
+}
+
+func TestLocationFailureConditionsPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts_location_failure_conditions/policies/42.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+server.URL+`/alerts_location_failure_conditions/policies/42.json?page=2>; rel="next", <`+server.URL+`/alerts_location_failure_conditions/policies/42.json?page=3>; rel="last"`)
+			w.Write([]byte(`{"location_failure_conditions": [{"id": 1}, {"id": 2}]}`))
+		case "2":
+			w.Header().Set("Link", `</alerts_location_failure_conditions/policies/42.json?page=3>; rel="next"`)
+			w.Write([]byte(`{"location_failure_conditions": [{"id": 3}]}`))
+		case "3":
+			w.Write([]byte(`{"location_failure_conditions": [{"id": 4}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	conditions, err := client.GetLocationFailureConditions(42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(conditions) != 4 {
+		t.Fatalf("expected 4 conditions from 3 pages, got %d", len(conditions))
+	}
+	for i, condition := range conditions {
+		if condition.ID != uint(i+1) {
+			t.Errorf("expected condition %d to have ID %d, got %d", i, i+1, condition.ID)
+		}
+	}
+
+	condition, err := client.GetLocationFailureCondition(42, 4)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if condition.ID != 4 {
+		t.Errorf("expected condition 4 from the last page, got %+v", condition)
+	}
+}
@@ -0,0 +1,24 @@
+package newrelic
+
+import (
+	"net/http"
+)
+
+// Location is a location Synthetics monitors can check from.
+type Location struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+}
+
+// GetLocations returns every public and private location available to
+// the account.
+func (c *Client) GetLocations() ([]*Location, error) {
+	var locations []*Location
+	if err := c.do(http.MethodGet, c.SyntheticsBaseURL+"/v1/locations", nil, &locations); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
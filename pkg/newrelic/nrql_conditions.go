@@ -0,0 +1,126 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNRQLConditionNotFound is returned when a NRQL alert condition
+// cannot be found.
+var ErrNRQLConditionNotFound = errors.New("newrelic: nrql condition not found")
+
+// NRQLTerm is a threshold at which a NRQL alert condition opens a
+// violation of the given priority. New Relic encodes the numeric
+// fields as strings.
+type NRQLTerm struct {
+	Duration     string `json:"duration"`
+	Operator     string `json:"operator"`
+	Priority     string `json:"priority"`
+	Threshold    string `json:"threshold"`
+	TimeFunction string `json:"time_function"`
+}
+
+// NRQLQuery is the query evaluated by a NRQL alert condition.
+type NRQLQuery struct {
+	Query      string `json:"query"`
+	SinceValue string `json:"since_value"`
+}
+
+// NRQLCondition is a NRQL alert condition.
+type NRQLCondition struct {
+	ID                        uint        `json:"id,omitempty"`
+	Type                      string      `json:"type,omitempty"`
+	Name                      string      `json:"name"`
+	Enabled                   bool        `json:"enabled"`
+	RunbookURL                string      `json:"runbook_url,omitempty"`
+	Terms                     []*NRQLTerm `json:"terms"`
+	ValueFunction             string      `json:"value_function"`
+	NRQL                      NRQLQuery   `json:"nrql"`
+	ViolationTimeLimitSeconds int         `json:"violation_time_limit_seconds,omitempty"`
+}
+
+type nrqlConditionBody struct {
+	NRQLCondition *NRQLCondition `json:"nrql_condition"`
+}
+
+type nrqlConditionsBody struct {
+	NRQLConditions []*NRQLCondition `json:"nrql_conditions"`
+}
+
+// GetNRQLConditions returns every NRQL alert condition attached to a
+// policy, from every page.
+func (c *Client) GetNRQLConditions(policyID uint) ([]*NRQLCondition, error) {
+	url := fmt.Sprintf("%s/alerts_nrql_conditions.json?policy_id=%d", c.AlertsBaseURL, policyID)
+
+	var conditions []*NRQLCondition
+	err := c.getPages(url, func() interface{} { return &nrqlConditionsBody{} }, func(body interface{}) {
+		conditions = append(conditions, body.(*nrqlConditionsBody).NRQLConditions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
+
+// GetNRQLCondition returns a NRQL alert condition by policy and
+// condition ID.
+func (c *Client) GetNRQLCondition(policyID uint, conditionID uint) (*NRQLCondition, error) {
+	conditions, err := c.GetNRQLConditions(policyID)
+	if isNotFound(err) {
+		return nil, ErrNRQLConditionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range conditions {
+		if condition.ID == conditionID {
+			return condition, nil
+		}
+	}
+
+	return nil, ErrNRQLConditionNotFound
+}
+
+// CreateNRQLCondition creates a NRQL alert condition on a policy.
+func (c *Client) CreateNRQLCondition(policyID uint, condition *NRQLCondition) (*NRQLCondition, error) {
+	url := fmt.Sprintf("%s/alerts_nrql_conditions/policies/%d.json", c.AlertsBaseURL, policyID)
+
+	var body nrqlConditionBody
+	if err := c.do(http.MethodPost, url, &nrqlConditionBody{condition}, &body); err != nil {
+		return nil, err
+	}
+
+	return body.NRQLCondition, nil
+}
+
+// UpdateNRQLCondition replaces a NRQL alert condition.
+func (c *Client) UpdateNRQLCondition(conditionID uint, condition *NRQLCondition) (*NRQLCondition, error) {
+	url := fmt.Sprintf("%s/alerts_nrql_conditions/%d.json", c.AlertsBaseURL, conditionID)
+
+	var body nrqlConditionBody
+	err := c.do(http.MethodPut, url, &nrqlConditionBody{condition}, &body)
+	if isNotFound(err) {
+		return nil, ErrNRQLConditionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return body.NRQLCondition, nil
+}
+
+// DeleteNRQLCondition deletes a NRQL alert condition.
+func (c *Client) DeleteNRQLCondition(conditionID uint) error {
+	url := fmt.Sprintf("%s/alerts_nrql_conditions/%d.json", c.AlertsBaseURL, conditionID)
+
+	err := c.do(http.MethodDelete, url, nil, nil)
+	if isNotFound(err) {
+		return ErrNRQLConditionNotFound
+	}
+
+	return err
+}
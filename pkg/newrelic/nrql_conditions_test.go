@@ -0,0 +1,56 @@
+package newrelic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestNRQLConditionsPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts_nrql_conditions.json" || r.URL.Query().Get("policy_id") != "42" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `</alerts_nrql_conditions.json?policy_id=42&page=2>; rel="next", </alerts_nrql_conditions.json?policy_id=42&page=2>; rel="last"`)
+			w.Write([]byte(`{"nrql_conditions": [{"id": 1, "nrql": {"query": "SELECT 1", "since_value": "3"}}]}`))
+		case "2":
+			w.Header().Set("Link", `</alerts_nrql_conditions.json?policy_id=42&page=1>; rel="first"`)
+			w.Write([]byte(`{"nrql_conditions": [{"id": 2, "nrql": {"query": "SELECT 2", "since_value": "3"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	conditions, err := client.GetNRQLConditions(42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(conditions) != 2 || conditions[1].NRQL.Query != "SELECT 2" {
+		t.Fatalf("expected both pages of conditions, got %+v", conditions)
+	}
+
+	condition, err := client.GetNRQLCondition(42, 2)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if condition.ID != 2 {
+		t.Errorf("expected condition 2 from the second page, got %+v", condition)
+	}
+	if _, err := client.GetNRQLCondition(42, 3); err != newrelic.ErrNRQLConditionNotFound {
+		t.Errorf("expected ErrNRQLConditionNotFound, got %v", err)
+	}
+}
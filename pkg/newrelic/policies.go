@@ -0,0 +1,37 @@
+package newrelic
+
+import (
+	"net/url"
+)
+
+// AlertPolicy is a New Relic alert policy.
+type AlertPolicy struct {
+	ID                 uint   `json:"id"`
+	Name               string `json:"name"`
+	IncidentPreference string `json:"incident_preference"`
+	CreatedAt          int64  `json:"created_at"`
+	UpdatedAt          int64  `json:"updated_at"`
+}
+
+type alertPoliciesBody struct {
+	Policies []*AlertPolicy `json:"policies"`
+}
+
+// GetAlertPolicies returns every alert policy whose name contains name,
+// or every alert policy if name is empty, from every page.
+func (c *Client) GetAlertPolicies(name string) ([]*AlertPolicy, error) {
+	u := c.AlertsBaseURL + "/alerts_policies.json"
+	if name != "" {
+		u += "?" + url.Values{"filter[name]": {name}}.Encode()
+	}
+
+	var policies []*AlertPolicy
+	err := c.getPages(u, func() interface{} { return &alertPoliciesBody{} }, func(body interface{}) {
+		policies = append(policies, body.(*alertPoliciesBody).Policies...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
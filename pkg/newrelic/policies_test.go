@@ -0,0 +1,44 @@
+package newrelic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestAlertPoliciesPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts_policies.json" || r.URL.Query().Get("filter[name]") != "On call" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `</alerts_policies.json?filter%5Bname%5D=On+call&page=2>; rel="next"`)
+			w.Write([]byte(`{"policies": [{"id": 1, "name": "On call"}]}`))
+		case "2":
+			w.Write([]byte(`{"policies": [{"id": 2, "name": "On call (EU)"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	policies, err := client.GetAlertPolicies("On call")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(policies) != 2 || policies[1].Name != "On call (EU)" {
+		t.Fatalf("expected the policies of both pages, got %+v", policies)
+	}
+}
@@ -0,0 +1,66 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSyntheticsConditionNotFound is returned when a Synthetics alert
+// condition cannot be found.
+var ErrSyntheticsConditionNotFound = errors.New("newrelic: synthetics condition not found")
+
+// SyntheticsCondition is a Synthetics alert condition that targets a
+// single monitor.
+type SyntheticsCondition struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	MonitorID  string `json:"monitor_id"`
+	RunbookURL string `json:"runbook_url,omitempty"`
+	Enabled    bool   `json:"enabled"`
+
+	// PolicyID is only sent when updating a condition, to ask New
+	// Relic to move it to another policy.
+	PolicyID uint `json:"policy_id,omitempty"`
+}
+
+type syntheticsConditionBody struct {
+	SyntheticsCondition *SyntheticsCondition `json:"synthetics_condition"`
+}
+
+type syntheticsConditionsBody struct {
+	SyntheticsConditions []*SyntheticsCondition `json:"synthetics_conditions"`
+}
+
+// GetSyntheticsConditions returns every Synthetics alert condition
+// attached to a policy, from every page.
+func (c *Client) GetSyntheticsConditions(policyID uint) ([]*SyntheticsCondition, error) {
+	url := fmt.Sprintf("%s/alerts_synthetics_conditions.json?policy_id=%d", c.AlertsBaseURL, policyID)
+
+	var conditions []*SyntheticsCondition
+	err := c.getPages(url, func() interface{} { return &syntheticsConditionsBody{} }, func(body interface{}) {
+		conditions = append(conditions, body.(*syntheticsConditionsBody).SyntheticsConditions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
+
+// UpdateSyntheticsCondition replaces a Synthetics alert condition.
+func (c *Client) UpdateSyntheticsCondition(conditionID uint, condition *SyntheticsCondition) (*SyntheticsCondition, error) {
+	url := fmt.Sprintf("%s/alerts_synthetics_conditions/%d.json", c.AlertsBaseURL, conditionID)
+
+	var body syntheticsConditionBody
+	err := c.do(http.MethodPut, url, &syntheticsConditionBody{condition}, &body)
+	if isNotFound(err) {
+		return nil, ErrSyntheticsConditionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return body.SyntheticsCondition, nil
+}
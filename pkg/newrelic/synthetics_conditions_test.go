@@ -0,0 +1,47 @@
+package newrelic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestSyntheticsConditionsPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts_synthetics_conditions.json" || r.URL.Query().Get("policy_id") != "42" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `</alerts_synthetics_conditions.json?policy_id=42&page=2>; rel="next"`)
+			w.Write([]byte(`{"synthetics_conditions": [{"id": 1, "name": "first", "monitor_id": "a"}]}`))
+		case "2":
+			w.Header().Set("Link", `<http://`+r.Host+`/alerts_synthetics_conditions.json?policy_id=42&page=3>; rel="next"`)
+			w.Write([]byte(`{"synthetics_conditions": [{"id": 2, "name": "second", "monitor_id": "b"}]}`))
+		case "3":
+			w.Write([]byte(`{"synthetics_conditions": [{"id": 3, "name": "third", "monitor_id": "c", "enabled": true}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	conditions, err := client.GetSyntheticsConditions(42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(conditions) != 3 || conditions[1].Name != "second" || !conditions[2].Enabled {
+		t.Fatalf("expected the conditions of all 3 pages, got %+v", conditions)
+	}
+}
@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/fakeapi"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// The acceptance tests run against a local stand-in for New Relic (see
+// pkg/fakeapi), configured through a provider block so that the
+// environment is left alone. They run against New Relic only when both
+// TF_ACC and NEWRELIC_API_KEY are set, in which case NEWRELIC_POLICY_ID
+// and NEWRELIC_POLICY_ID_2 must name two alert policies the tests may
+// add conditions to.
+
+var (
+	testAccProvider  = Provider().(*nrsProvider)
+	testAccProviders = map[string]terraform.ResourceProvider{"nrs": testAccProvider}
+
+	testAccLive                = os.Getenv(resource.TestEnvVar) != "" && os.Getenv("NEWRELIC_API_KEY") != ""
+	testAccProviderConfig      string
+	testAccPolicyID            = "1"
+	testAccPolicyID2           = "2"
+	testAccName                = fmt.Sprintf("tf-acc-%d", time.Now().UnixNano())
+	testAccMonitorResourceName = "nrs_monitor.test"
+)
+
+func TestMain(m *testing.M) {
+	if testAccLive {
+		testAccPolicyID = os.Getenv("NEWRELIC_POLICY_ID")
+		testAccPolicyID2 = os.Getenv("NEWRELIC_POLICY_ID_2")
+		os.Exit(m.Run())
+	}
+
+	_, server := fakeapi.NewServer("")
+	testAccProviderConfig = fmt.Sprintf(`provider "nrs" {
+  newrelic_api_key    = "stand-in"
+  synthetics_base_url = %q
+  alerts_base_url     = %q
+}
+`, server.URL+fakeapi.SyntheticsPath, server.URL+fakeapi.AlertsPath)
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+func testAccPreCheck(t *testing.T) {
+	if testAccLive && (testAccPolicyID == "" || testAccPolicyID2 == "") {
+		t.Fatal("NEWRELIC_POLICY_ID and NEWRELIC_POLICY_ID_2 must be set for acceptance tests against New Relic")
+	}
+}
+
+// testAccTest runs an acceptance test case against New Relic or, unless
+// the tests are live, against the stand-in, whose provider block is
+// added to every step.
+func testAccTest(t *testing.T, c resource.TestCase) {
+	c.PreCheck = func() { testAccPreCheck(t) }
+	c.Providers = testAccProviders
+	c.IsUnitTest = !testAccLive
+	for i := range c.Steps {
+		c.Steps[i].Config = testAccProviderConfig + c.Steps[i].Config
+	}
+
+	resource.Test(t, c)
+}
+
+func testAccMeta() *providerMeta {
+	return testAccProvider.Meta().(*providerMeta)
+}
+
+// testAccConfig renders a resource block from attributes given as HCL
+// values.
+func testAccConfig(resourceType, name string, attributes map[string]string) string {
+	var keys []string
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "resource %q %q {\n", resourceType, name)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  %s = %s\n", key, attributes[key])
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// with returns a copy of attributes with key set to value.
+func with(attributes map[string]string, key, value string) map[string]string {
+	copied := map[string]string{key: value}
+	for k, v := range attributes {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// testAccCaptureID stores the ID of a resource, so that later steps can
+// delete it out of band or check that it changed.
+func testAccCaptureID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckIDChanged checks that a resource was recreated.
+func testAccCheckIDChanged(name string, old *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		if rs.Primary.ID == *old {
+			return fmt.Errorf("expected %s to be recreated, still has ID %s", name, *old)
+		}
+		return nil
+	}
+}
+
+func testAccCheckMonitorDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nrs_monitor" {
+			continue
+		}
+		_, err := testAccMeta().synthetics.GetMonitor(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("monitor %s still exists", rs.Primary.ID)
+		}
+		if err != synthetics.ErrMonitorNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAlertConditionDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nrs_alert_condition" {
+			continue
+		}
+		id, err := strconv.ParseUint(rs.Primary.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+		policyID, err := strconv.ParseUint(rs.Primary.Attributes["policy_id"], 10, 64)
+		if err != nil {
+			return err
+		}
+		_, err = testAccMeta().synthetics.GetAlertCondition(uint(policyID), uint(id))
+		if err == nil {
+			return fmt.Errorf("alert condition %s still exists", rs.Primary.ID)
+		}
+		if err != synthetics.ErrAlertConditionNotFound {
+			return err
+		}
+	}
+
+	return testAccCheckMonitorDestroy(s)
+}
+
+func TestAccMonitor(t *testing.T) {
+	attributes := map[string]string{
+		"name":      fmt.Sprintf("%q", testAccName),
+		"type":      `"SIMPLE"`,
+		"frequency": "5",
+		"uri":       `"https://example.com"`,
+		"locations": `["AWS_US_WEST_1"]`,
+		"status":    `"ENABLED"`,
+	}
+
+	steps := []resource.TestStep{
+		{
+			Config: testAccConfig("nrs_monitor", "test", attributes),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet(testAccMonitorResourceName, "id"),
+				resource.TestCheckResourceAttr(testAccMonitorResourceName, "name", testAccName),
+				resource.TestCheckResourceAttr(testAccMonitorResourceName, "frequency", "5"),
+				resource.TestCheckResourceAttr(testAccMonitorResourceName, "locations.#", "1"),
+			),
+		},
+	}
+
+	// Update each attribute in turn.
+	for _, update := range []struct {
+		key, value string
+		check      resource.TestCheckFunc
+	}{
+		{"name", fmt.Sprintf("%q", testAccName+"-renamed"), resource.TestCheckResourceAttr(testAccMonitorResourceName, "name", testAccName+"-renamed")},
+		{"frequency", "15", resource.TestCheckResourceAttr(testAccMonitorResourceName, "frequency", "15")},
+		{"uri", `"https://example.com/health"`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "uri", "https://example.com/health")},
+		{"locations", `["AWS_US_WEST_1", "AWS_US_EAST_1"]`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "locations.#", "2")},
+		{"status", `"MUTED"`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "status", "MUTED")},
+		{"sla_threshold", "3", resource.TestCheckResourceAttr(testAccMonitorResourceName, "sla_threshold", "3")},
+		{"validation_string", `"ok"`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "validation_string", "ok")},
+		{"verify_ssl", "true", resource.TestCheckResourceAttr(testAccMonitorResourceName, "verify_ssl", "true")},
+		{"bypass_head_request", "true", resource.TestCheckResourceAttr(testAccMonitorResourceName, "bypass_head_request", "true")},
+		{"treat_redirect_as_failure", "true", resource.TestCheckResourceAttr(testAccMonitorResourceName, "treat_redirect_as_failure", "true")},
+		{"labels", `{ Team = "web" }`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "labels.Team", "web")},
+		{"labels", `{ Team = "api", Env = "test" }`, resource.TestCheckResourceAttr(testAccMonitorResourceName, "tags_all.%", "2")},
+	} {
+		attributes = with(attributes, update.key, update.value)
+		steps = append(steps, resource.TestStep{
+			Config: testAccConfig("nrs_monitor", "test", attributes),
+			Check:  update.check,
+		})
+	}
+
+	var id string
+	steps = append(steps,
+		resource.TestStep{
+			ResourceName:            testAccMonitorResourceName,
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"adopt_existing"},
+		},
+		// A monitor deleted out of band is created again.
+		resource.TestStep{
+			Config: testAccConfig("nrs_monitor", "test", attributes),
+			Check:  testAccCaptureID(testAccMonitorResourceName, &id),
+		},
+		resource.TestStep{
+			PreConfig: func() {
+				if err := testAccMeta().synthetics.DeleteMonitor(id); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+			},
+			Config: testAccConfig("nrs_monitor", "test", attributes),
+			Check:  testAccCheckIDChanged(testAccMonitorResourceName, &id),
+		},
+	)
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckMonitorDestroy,
+		Steps:        steps,
+	})
+}
+
+func TestAccMonitorScript(t *testing.T) {
+	attributes := map[string]string{
+		"name":      fmt.Sprintf("%q", testAccName+"-script"),
+		"type":      `"SCRIPT_API"`,
+		"frequency": "5",
+		"locations": `["AWS_US_WEST_1"]`,
+		"status":    `"ENABLED"`,
+		"script":    `"console.log('check')"`,
+	}
+	updated := with(attributes, "script", `"console.log('check again')"`)
+	updated = with(updated, "script_locations", `[{ name = "private", hmac = "secret" }]`)
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckMonitorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfig("nrs_monitor", "test", attributes),
+				Check:  resource.TestCheckResourceAttr(testAccMonitorResourceName, "script", sha256StateFunc("console.log('check')")),
+			},
+			{
+				Config: testAccConfig("nrs_monitor", "test", updated),
+				Check:  resource.TestCheckResourceAttr(testAccMonitorResourceName, "script", sha256StateFunc("console.log('check again')")),
+			},
+			{
+				// New Relic doesn't return script locations, so they
+				// are missing from the imported state.
+				ResourceName:            testAccMonitorResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing", "script_locations"},
+			},
+		},
+	})
+}
+
+// testAccAlertConditionConfig renders two monitors and an alert
+// condition.
+func testAccAlertConditionConfig(attributes map[string]string) string {
+	var b bytes.Buffer
+	for _, name := range []string{"first", "second"} {
+		b.WriteString(testAccConfig("nrs_monitor", name, map[string]string{
+			"name":      fmt.Sprintf("%q", testAccName+"-"+name),
+			"type":      `"SIMPLE"`,
+			"frequency": "5",
+			"uri":       `"https://example.com"`,
+			"locations": `["AWS_US_WEST_1"]`,
+			"status":    `"ENABLED"`,
+		}))
+	}
+	b.WriteString(testAccConfig("nrs_alert_condition", "test", attributes))
+
+	return b.String()
+}
+
+func TestAccAlertCondition(t *testing.T) {
+	name := "nrs_alert_condition.test"
+	attributes := map[string]string{
+		"name":       fmt.Sprintf("%q", testAccName),
+		"monitor_id": `"${nrs_monitor.first.id}"`,
+		"policy_id":  testAccPolicyID,
+		"enabled":    "true",
+	}
+
+	steps := []resource.TestStep{
+		{
+			Config: testAccAlertConditionConfig(attributes),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet(name, "id"),
+				resource.TestCheckResourceAttr(name, "name", testAccName),
+				resource.TestCheckResourceAttr(name, "enabled", "true"),
+			),
+		},
+	}
+
+	for _, update := range []struct {
+		key, value string
+		check      resource.TestCheckFunc
+	}{
+		{"name", fmt.Sprintf("%q", testAccName+"-renamed"), resource.TestCheckResourceAttr(name, "name", testAccName+"-renamed")},
+		{"runbook_url", `"https://example.com/runbook"`, resource.TestCheckResourceAttr(name, "runbook_url", "https://example.com/runbook")},
+		{"enabled", "false", resource.TestCheckResourceAttr(name, "enabled", "false")},
+		{"monitor_id", `"${nrs_monitor.second.id}"`, resource.TestCheckResourceAttrSet(name, "monitor_id")},
+		{"policy_id", testAccPolicyID2, resource.TestCheckResourceAttr(name, "policy_id", testAccPolicyID2)},
+	} {
+		attributes = with(attributes, update.key, update.value)
+		steps = append(steps, resource.TestStep{
+			Config: testAccAlertConditionConfig(attributes),
+			Check:  update.check,
+		})
+	}
+
+	var id string
+	steps = append(steps,
+		resource.TestStep{
+			ResourceName:        name,
+			ImportState:         true,
+			ImportStateIdPrefix: testAccPolicyID2 + ":",
+			ImportStateVerify:   true,
+		},
+		// An alert condition deleted out of band is created again.
+		resource.TestStep{
+			Config: testAccAlertConditionConfig(attributes),
+			Check:  testAccCaptureID(name, &id),
+		},
+		resource.TestStep{
+			PreConfig: func() {
+				conditionID, err := strconv.ParseUint(id, 10, 64)
+				if err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				if err := testAccMeta().synthetics.DeleteAlertCondition(uint(conditionID)); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+			},
+			Config: testAccAlertConditionConfig(attributes),
+			Check:  testAccCheckIDChanged(name, &id),
+		},
+	)
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckAlertConditionDestroy,
+		Steps:        steps,
+	})
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// baseURLTransport sends requests for New Relic's base URLs to other
+// base URLs, e.g. to a local stand-in for New Relic. new-relic-synthetics-go
+// has its base URLs built in, so they are overridden here rather than
+// in the client.
+type baseURLTransport struct {
+	// bases maps default base URLs to the base URLs to use instead.
+	bases map[string]*url.URL
+	next  http.RoundTripper
+}
+
+// RoundTrip rewrites the URL of requests for an overridden base URL
+// and sends them on.
+func (t *baseURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := req.URL.String()
+	for from, to := range t.bases {
+		if !strings.HasPrefix(u, from) {
+			continue
+		}
+
+		rewritten, err := url.Parse(to.String() + strings.TrimPrefix(u, from))
+		if err != nil {
+			return nil, err
+		}
+
+		// RoundTrippers must not modify the request they are given.
+		clone := new(http.Request)
+		*clone = *req
+		clone.URL = rewritten
+		clone.Host = rewritten.Host
+		return t.next.RoundTrip(clone)
+	}
+
+	return t.next.RoundTrip(req)
+}
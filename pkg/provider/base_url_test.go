@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestBaseURLTransport(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+	}))
+	defer server.Close()
+
+	to, err := url.Parse(server.URL + "/synthetics/api")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &http.Client{Transport: &baseURLTransport{
+		bases: map[string]*url.URL{newrelic.DefaultSyntheticsBaseURL: to},
+		next:  http.DefaultTransport,
+	}}
+
+	resp, err := client.Get(newrelic.DefaultSyntheticsBaseURL + "/v3/monitors?offset=0&limit=100")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if len(paths) != 1 || paths[0] != "/synthetics/api/v3/monitors?offset=0&limit=100" {
+		t.Errorf("expected the request to be sent to the override, got %v", paths)
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+// syntheticsClient is the part of the Synthetics client the provider
+// uses. Resources and data sources depend on it rather than on
+// *synthetics.Client, so that they can be tested against fake.Client.
+type syntheticsClient interface {
+	monitorClient
+	alertConditionClient
+}
+
+// monitorClient is the part of the Synthetics client used to manage
+// monitors and their scripts.
+type monitorClient interface {
+	GetAllMonitors(offset uint, limit uint) (*synthetics.GetAllMonitorsResponse, error)
+	GetMonitor(id string) (*synthetics.ExtendedMonitor, error)
+	CreateMonitor(args *synthetics.CreateMonitorArgs) (*synthetics.ExtendedMonitor, error)
+	UpdateMonitor(id string, args *synthetics.UpdateMonitorArgs) (*synthetics.ExtendedMonitor, error)
+	DeleteMonitor(id string) error
+	GetMonitorScript(id string) (string, error)
+	UpdateMonitorScript(id string, args *synthetics.UpdateMonitorScriptArgs) error
+}
+
+// alertConditionClient is the part of the Synthetics client used to
+// manage alert conditions.
+type alertConditionClient interface {
+	CreateAlertCondition(policyID uint, args *synthetics.CreateAlertConditionArgs) (*synthetics.AlertCondition, error)
+	GetAlertCondition(policyID uint, alertConditionID uint) (*synthetics.AlertCondition, error)
+	UpdateAlertCondition(alertConditionID uint, args *synthetics.UpdateAlertConditionArgs) (*synthetics.AlertCondition, error)
+	DeleteAlertCondition(alertConditionID uint) error
+}
+
+// monitorLabelClient is the part of the New Relic client used to
+// manage monitor labels.
+type monitorLabelClient interface {
+	GetMonitorLabels(monitorID string) ([]*newrelic.MonitorLabel, error)
+	AddMonitorLabel(monitorID string, label *newrelic.MonitorLabel) error
+	DeleteMonitorLabel(monitorID string, label *newrelic.MonitorLabel) error
+}
+
+// alertPolicyClient is the part of the New Relic client used to list
+// alert policies and their Synthetics alert conditions, and to move
+// conditions between policies.
+type alertPolicyClient interface {
+	GetAlertPolicies(name string) ([]*newrelic.AlertPolicy, error)
+	GetSyntheticsConditions(policyID uint) ([]*newrelic.SyntheticsCondition, error)
+	UpdateSyntheticsCondition(conditionID uint, condition *newrelic.SyntheticsCondition) (*newrelic.SyntheticsCondition, error)
+}
+
+// locationFailureConditionClient is the part of the New Relic client
+// used to manage multi-location alert conditions.
+type locationFailureConditionClient interface {
+	GetLocationFailureConditions(policyID uint) ([]*newrelic.LocationFailureCondition, error)
+	GetLocationFailureCondition(policyID uint, conditionID uint) (*newrelic.LocationFailureCondition, error)
+	CreateLocationFailureCondition(policyID uint, condition *newrelic.LocationFailureCondition) (*newrelic.LocationFailureCondition, error)
+	UpdateLocationFailureCondition(conditionID uint, condition *newrelic.LocationFailureCondition) (*newrelic.LocationFailureCondition, error)
+	DeleteLocationFailureCondition(conditionID uint) error
+}
+
+// nrqlConditionClient is the part of the New Relic client used to
+// manage NRQL alert conditions.
+type nrqlConditionClient interface {
+	GetNRQLConditions(policyID uint) ([]*newrelic.NRQLCondition, error)
+	GetNRQLCondition(policyID uint, conditionID uint) (*newrelic.NRQLCondition, error)
+	CreateNRQLCondition(policyID uint, condition *newrelic.NRQLCondition) (*newrelic.NRQLCondition, error)
+	UpdateNRQLCondition(conditionID uint, condition *newrelic.NRQLCondition) (*newrelic.NRQLCondition, error)
+	DeleteNRQLCondition(conditionID uint) error
+}
+
+// locationClient is the part of the New Relic client used to list
+// Synthetics locations.
+type locationClient interface {
+	GetLocations() ([]*newrelic.Location, error)
+}
+
+// insightsClient is the part of the New Relic client used to run NRQL
+// queries.
+type insightsClient interface {
+	Query(nrql string) (*newrelic.InsightsResponse, error)
+}
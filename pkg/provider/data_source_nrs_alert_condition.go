@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// NRSAlertConditionDataSource returns a Terraform schema for looking up
+// the Synthetics alert condition a policy has for a monitor.
+func NRSAlertConditionDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"policy_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The ID of the policy the alert condition is attached to",
+			},
+			"monitor_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the monitor the alert condition targets",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the alert condition",
+			},
+			"runbook_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL to a runbook for addressing the alert",
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the alert condition is enabled",
+			},
+		},
+		Read: NRSAlertConditionDataSourceRead,
+	}
+}
+
+// NRSAlertConditionDataSourceRead looks up the only Synthetics alert
+// condition in a policy that targets a monitor.
+func NRSAlertConditionDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	policyID := uint(resourceData.Get("policy_id").(int))
+	monitorID := resourceData.Get("monitor_id").(string)
+
+	condition, err := findAlertCondition(meta, policyID, fmt.Sprintf("monitor %q", monitorID), func(c *newrelic.SyntheticsCondition) bool {
+		return c.MonitorID == monitorID
+	})
+	if err != nil {
+		return err
+	}
+
+	resourceData.SetId(fmt.Sprintf("%d", condition.ID))
+	if err := resourceData.Set("name", condition.Name); err != nil {
+		return err
+	}
+	if err := resourceData.Set("runbook_url", condition.RunbookURL); err != nil {
+		return err
+	}
+	if err := resourceData.Set("enabled", condition.Enabled); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+)
+
+func TestAlertConditionDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	dataSource := NRSAlertConditionDataSource()
+
+	for id, condition := range map[uint]*synthetics.AlertCondition{
+		10: {ID: 10, Name: "web down", MonitorID: "web", RunbookURL: "https://example.com/runbook", Enabled: true},
+		11: {ID: 11, Name: "api down", MonitorID: "api"},
+		12: {ID: 12, Name: "api down again", MonitorID: "api"},
+		13: {ID: 13, Name: "other policy", MonitorID: "other"},
+	} {
+		client.AlertConditions[id] = condition
+		client.AlertConditionPolicies[id] = 7
+	}
+	client.AlertConditionPolicies[13] = 8
+
+	tests := []struct {
+		monitorID string
+		id        string
+		err       string
+	}{
+		{monitorID: "web", id: "10"},
+		{monitorID: "other", err: `no alert condition in policy 7 has monitor "other"`},
+		{monitorID: "api", err: `2 alert conditions in policy 7 have monitor "api" (11, 12)`},
+	}
+
+	for _, test := range tests {
+		resourceData := dataSource.Data(nil)
+		for key, value := range map[string]interface{}{"policy_id": 7, "monitor_id": test.monitorID} {
+			if err := resourceData.Set(key, value); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+
+		err := NRSAlertConditionDataSourceRead(resourceData, meta)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected an error containing %q, got %v", test.monitorID, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %s", test.monitorID, err)
+		}
+		checkAttributes(t, resourceData.State(), map[string]string{
+			"id":          test.id,
+			"name":        "web down",
+			"runbook_url": "https://example.com/runbook",
+			"enabled":     "true",
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// NRSAlertPolicyDataSource returns a Terraform schema for looking up a
+// New Relic alert policy by name.
+func NRSAlertPolicyDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The exact name of the alert policy",
+			},
+			"incident_preference": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the policy groups violations into incidents (one of PER_POLICY, PER_CONDITION, PER_CONDITION_AND_TARGET)",
+			},
+			"created_at": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the policy was created, in milliseconds since the epoch",
+			},
+			"updated_at": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the policy was last updated, in milliseconds since the epoch",
+			},
+		},
+		Read: NRSAlertPolicyDataSourceRead,
+	}
+}
+
+// NRSAlertPolicyDataSourceRead looks up an alert policy by exact name.
+func NRSAlertPolicyDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).alertPolicies
+
+	name := resourceData.Get("name").(string)
+	policies, err := client.GetAlertPolicies(name)
+	if err != nil {
+		return errors.Wrap(err, "error: could not list alert policies")
+	}
+
+	// New Relic filters policies by substring, so names are matched
+	// exactly here.
+	var ids []string
+	index := -1
+	for i, policy := range policies {
+		if policy.Name == name {
+			index = i
+			ids = append(ids, fmt.Sprintf("%d", policy.ID))
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return fmt.Errorf("error: no alert policy is named %q", name)
+	case 1:
+	default:
+		return fmt.Errorf("error: %d alert policies are named %q (%s)", len(ids), name, strings.Join(ids, ", "))
+	}
+
+	policy := policies[index]
+	resourceData.SetId(fmt.Sprintf("%d", policy.ID))
+	if err := resourceData.Set("incident_preference", policy.IncidentPreference); err != nil {
+		return err
+	}
+	if err := resourceData.Set("created_at", int(policy.CreatedAt)); err != nil {
+		return err
+	}
+	if err := resourceData.Set("updated_at", int(policy.UpdatedAt)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestAlertPolicyDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	dataSource := NRSAlertPolicyDataSource()
+
+	// New Relic finds "On call" in all of these names.
+	client.Policies[1] = &newrelic.AlertPolicy{ID: 1, Name: "On call (EU)"}
+	client.Policies[2] = &newrelic.AlertPolicy{ID: 2, Name: "On call", IncidentPreference: "PER_POLICY", CreatedAt: 100, UpdatedAt: 200}
+	client.Policies[3] = &newrelic.AlertPolicy{ID: 3, Name: "Web"}
+	client.Policies[4] = &newrelic.AlertPolicy{ID: 4, Name: "Web"}
+
+	tests := []struct {
+		name string
+		id   string
+		err  string
+	}{
+		{name: "On call", id: "2"},
+		{name: "On", err: `no alert policy is named "On"`},
+		{name: "Web", err: `2 alert policies are named "Web" (3, 4)`},
+	}
+
+	for _, test := range tests {
+		resourceData := dataSource.Data(nil)
+		if err := resourceData.Set("name", test.name); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		err := NRSAlertPolicyDataSourceRead(resourceData, meta)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected an error containing %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %s", test.name, err)
+		}
+		checkAttributes(t, resourceData.State(), map[string]string{
+			"id":                  test.id,
+			"incident_preference": "PER_POLICY",
+			"created_at":          "100",
+			"updated_at":          "200",
+		})
+	}
+}
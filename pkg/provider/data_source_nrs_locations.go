@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// NRSLocationsDataSource returns a Terraform schema for listing the
+// locations Synthetics monitors can check from.
+func NRSLocationsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"region": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The region of locations to list (e.g. US, EU, AP)",
+			},
+			// A string, so that an unset filter can be told apart
+			// from false.
+			"private": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Whether to list only private (true) or only public (false) locations",
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if _, err := strconv.ParseBool(i.(string)); err != nil {
+						return nil, []error{fmt.Errorf("%s must be a boolean, got %q", k, i.(string))}
+					}
+					return nil, nil
+				},
+			},
+			"names": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of the matching locations, as used by nrs_monitor",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"locations": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching locations, in the order of names",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the location",
+						},
+						"label": &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The human readable label of the location",
+						},
+						"region": &schema.Schema{
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The region of the location (US, EU or AP); empty for private locations and locations in other countries",
+						},
+						"private": &schema.Schema{
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the location is private",
+						},
+					},
+				},
+			},
+		},
+		Read: NRSLocationsDataSourceRead,
+	}
+}
+
+// locationRegions maps the country that ends a location's label (e.g.
+// "Dublin, IE") to its region.
+var locationRegions = map[string]string{
+	"USA": "US",
+	"IE":  "EU",
+	"UK":  "EU",
+	"GB":  "EU",
+	"DE":  "EU",
+	"FR":  "EU",
+	"IT":  "EU",
+	"SE":  "EU",
+	"JP":  "AP",
+	"KR":  "AP",
+	"SG":  "AP",
+	"AU":  "AP",
+	"IN":  "AP",
+	"HK":  "AP",
+}
+
+// locationRegion returns the region of a public location. New Relic
+// doesn't return regions, so the region comes from the country at the
+// end of the location's label; locations in other countries, and
+// private locations, have no region.
+func locationRegion(location *newrelic.Location) string {
+	if location.Private {
+		return ""
+	}
+
+	parts := strings.Split(location.Label, ",")
+	return locationRegions[strings.TrimSpace(parts[len(parts)-1])]
+}
+
+// NRSLocationsDataSourceRead lists the locations available to the
+// account that match the configured filters.
+func NRSLocationsDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).locations
+
+	region := resourceData.Get("region").(string)
+	private := resourceData.Get("private").(string)
+	var wantPrivate bool
+	if private != "" {
+		var err error
+		wantPrivate, err = strconv.ParseBool(private)
+		if err != nil {
+			return errors.Wrap(err, "error: invalid private filter")
+		}
+	}
+
+	locations, err := client.GetLocations()
+	if err != nil {
+		return errors.Wrap(err, "error: could not list locations")
+	}
+
+	names := []string{}
+	matches := []map[string]interface{}{}
+	for _, location := range locations {
+		if region != "" && !strings.EqualFold(locationRegion(location), region) {
+			continue
+		}
+		if private != "" && location.Private != wantPrivate {
+			continue
+		}
+
+		names = append(names, location.Name)
+		matches = append(matches, map[string]interface{}{
+			"name":    location.Name,
+			"label":   location.Label,
+			"region":  locationRegion(location),
+			"private": location.Private,
+		})
+	}
+
+	resourceData.SetId(fmt.Sprintf("%d", hashcode.String(region+":"+private)))
+	if err := resourceData.Set("names", names); err != nil {
+		return err
+	}
+	if err := resourceData.Set("locations", matches); err != nil {
+		return err
+	}
+
+	return nil
+}
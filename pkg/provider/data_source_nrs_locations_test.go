@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestLocationRegion(t *testing.T) {
+	tests := []struct {
+		location newrelic.Location
+		expected string
+	}{
+		{location: newrelic.Location{Name: "AWS_US_WEST_1", Label: "San Francisco, CA, USA"}, expected: "US"},
+		{location: newrelic.Location{Name: "AWS_EU_WEST_1", Label: "Dublin, IE"}, expected: "EU"},
+		{location: newrelic.Location{Name: "AWS_EU_WEST_2", Label: "London, England, UK"}, expected: "EU"},
+		{location: newrelic.Location{Name: "AWS_AP_NORTHEAST_1", Label: "Tokyo, JP"}, expected: "AP"},
+		{location: newrelic.Location{Name: "AWS_CA_CENTRAL_1", Label: "Montreal, Québec, CA"}, expected: ""},
+		{location: newrelic.Location{Name: "LINODE_US_EAST_1", Label: "Newark, NJ"}, expected: ""},
+		{location: newrelic.Location{Name: "AWS_US_PRIVATE", Label: "Office, USA", Private: true}, expected: ""},
+	}
+
+	for _, test := range tests {
+		if region := locationRegion(&test.location); region != test.expected {
+			t.Errorf("%s: expected region %q, got %q", test.location.Name, test.expected, region)
+		}
+	}
+}
+
+func TestLocationsDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	client.Locations = []*newrelic.Location{
+		{Name: "AWS_US_WEST_1", Label: "San Francisco, CA, USA"},
+		{Name: "AWS_EU_WEST_1", Label: "Dublin, IE"},
+		{Name: "AWS_CA_CENTRAL_1", Label: "Montreal, Québec, CA"},
+		{Name: "office", Label: "Office, USA", Private: true},
+	}
+	dataSource := NRSLocationsDataSource()
+
+	tests := []struct {
+		config   map[string]interface{}
+		expected []string
+	}{
+		{config: map[string]interface{}{}, expected: []string{"AWS_US_WEST_1", "AWS_EU_WEST_1", "AWS_CA_CENTRAL_1", "office"}},
+		{config: map[string]interface{}{"region": "us"}, expected: []string{"AWS_US_WEST_1"}},
+		{config: map[string]interface{}{"region": "EU"}, expected: []string{"AWS_EU_WEST_1"}},
+		{config: map[string]interface{}{"region": "CA"}, expected: []string{}},
+		{config: map[string]interface{}{"private": "true"}, expected: []string{"office"}},
+		{config: map[string]interface{}{"private": "false"}, expected: []string{"AWS_US_WEST_1", "AWS_EU_WEST_1", "AWS_CA_CENTRAL_1"}},
+		{config: map[string]interface{}{"region": "US", "private": "true"}, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		resourceData := dataSource.Data(nil)
+		for key, value := range test.config {
+			if err := resourceData.Set(key, value); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+		if err := NRSLocationsDataSourceRead(resourceData, meta); err != nil {
+			t.Fatalf("%v: err: %s", test.config, err)
+		}
+
+		names := []string{}
+		for _, name := range resourceData.Get("names").([]interface{}) {
+			names = append(names, name.(string))
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.config, test.expected, names)
+		}
+	}
+
+	resourceData := dataSource.Data(nil)
+	if err := NRSLocationsDataSourceRead(resourceData, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if region := resourceData.Get("locations.2.region").(string); region != "" {
+		t.Errorf("expected no region for a location outside US, EU and AP, got %q", region)
+	}
+	if region := resourceData.Get("locations.1.region").(string); region != "EU" {
+		t.Errorf("expected region EU, got %q", region)
+	}
+}
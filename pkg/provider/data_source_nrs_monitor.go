@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// NRSMonitorDataSource returns a Terraform schema for looking up an
+// existing New Relic Synthetics monitor.
+func NRSMonitorDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"monitor_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "The ID of the monitor to look up",
+				ConflictsWith: []string{"name"},
+			},
+			"name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "The exact name of the monitor to look up",
+				ConflictsWith: []string{"monitor_id"},
+			},
+			"type": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of monitor (one of SIMPLE, BROWSER, SCRIPT_API, SCRIPT_BROWSER)",
+			},
+			"frequency": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The monitor's checking frequency in minutes",
+			},
+			"uri": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL to monitor",
+			},
+			"locations": &schema.Schema{
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The locations to check from",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"status": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The monitor's status (one of ENABLED, MUTED, DISABLED)",
+			},
+			"sla_threshold": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "The monitor's SLA threshold",
+			},
+			"validation_string": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The monitor's validation string",
+			},
+			"verify_ssl": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Verify SSL",
+			},
+			"bypass_head_request": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Bypass HEAD request",
+			},
+			"treat_redirect_as_failure": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Treat redirect as failure",
+			},
+			"labels": &schema.Schema{
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The monitor's labels, as a map of category to label",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"script": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hash of the monitor's script, as stored by nrs_monitor",
+			},
+		},
+		Read: NRSMonitorDataSourceRead,
+	}
+}
+
+// NRSMonitorDataSourceRead looks up a Synthetics monitor by ID or by
+// exact name.
+func NRSMonitorDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).synthetics
+
+	var monitorID string
+	if data, ok := resourceData.GetOk("monitor_id"); ok {
+		monitorID = data.(string)
+	} else if data, ok := resourceData.GetOk("name"); ok {
+		monitor, err := findMonitorByName(client, data.(string))
+		if err != nil {
+			return err
+		}
+		monitorID = monitor.ID
+	} else {
+		return errors.New("error: one of monitor_id or name must be set")
+	}
+
+	monitor, err := client.GetMonitor(monitorID)
+	if err != nil {
+		return errors.Wrapf(err, "error: could not get monitor %s", monitorID)
+	}
+
+	resourceData.SetId(monitor.ID)
+	if err := resourceData.Set("monitor_id", monitor.ID); err != nil {
+		return err
+	}
+
+	return readMonitor(resourceData, meta.(*providerMeta), monitor)
+}
@@ -0,0 +1,299 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// maxResultLocations is the most locations check results are
+// summarized for. Insights returns 10 facets unless told otherwise.
+const maxResultLocations = 100
+
+// NRSMonitorResultsDataSource returns a Terraform schema for
+// summarizing the recent check results of a Synthetics monitor.
+func NRSMonitorResultsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"monitor_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the monitor",
+			},
+			"since_minutes": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				Description:  "How many minutes of check results to look at",
+				ValidateFunc: validation.IntBetween(1, 10080),
+			},
+			"locations": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The locations to look at; all locations when unset",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"last_checks": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "How many of the latest checks in each location must have passed for passing to be true; all checks in the window when unset",
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"passing": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the latest checks passed in every location that reported results",
+			},
+			"passed": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of checks that passed",
+			},
+			"failed": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of checks that failed",
+			},
+			"average_duration": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "The average check duration in milliseconds",
+			},
+			"latest": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The latest check result in each location, sorted by location",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"location": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"result": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"duration": &schema.Schema{
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"timestamp": &schema.Schema{
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "When the check ran, in milliseconds since the epoch",
+						},
+					},
+				},
+			},
+		},
+		Read: NRSMonitorResultsDataSourceRead,
+	}
+}
+
+// checkResult is a SyntheticCheck event.
+type checkResult struct {
+	location  string
+	result    string
+	duration  float64
+	timestamp int64
+}
+
+// locationChecks summarizes the check results of one location.
+// latest holds its latest results, newest first.
+type locationChecks struct {
+	location        string
+	count, passed   int
+	averageDuration float64
+	latest          []*checkResult
+}
+
+// checkSummary summarizes check results.
+type checkSummary struct {
+	passing         bool
+	passed, failed  int
+	averageDuration float64
+	latest          []*checkResult
+}
+
+// summarizeChecks summarizes the check results of every location.
+// passing is true when the latest lastChecks results of every location
+// passed, or every result passed if lastChecks is zero. passing is
+// false without results.
+func summarizeChecks(locations []*locationChecks, lastChecks int) *checkSummary {
+	summary := &checkSummary{passing: len(locations) > 0}
+
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].location < locations[j].location
+	})
+
+	var count int
+	var totalDuration float64
+	for _, location := range locations {
+		count += location.count
+		summary.passed += location.passed
+		summary.failed += location.count - location.passed
+		totalDuration += location.averageDuration * float64(location.count)
+		if len(location.latest) > 0 {
+			summary.latest = append(summary.latest, location.latest[0])
+		}
+
+		if lastChecks == 0 {
+			if location.passed < location.count {
+				summary.passing = false
+			}
+			continue
+		}
+		if len(location.latest) < lastChecks {
+			summary.passing = false
+			continue
+		}
+		for _, check := range location.latest[:lastChecks] {
+			if check.result != "SUCCESS" {
+				summary.passing = false
+			}
+		}
+	}
+	if count > 0 {
+		summary.averageDuration = totalDuration / float64(count)
+	}
+
+	return summary
+}
+
+// checkResultFromEvent reads a SyntheticCheck event from Insights.
+func checkResultFromEvent(event map[string]interface{}) *checkResult {
+	result := &checkResult{}
+	result.location, _ = event["location"].(string)
+	result.result, _ = event["result"].(string)
+	result.duration, _ = event["duration"].(float64)
+	if timestamp, ok := event["timestamp"].(float64); ok {
+		result.timestamp = int64(timestamp)
+	}
+
+	return result
+}
+
+// facetNumber returns the value of the ith function selected by a
+// faceted query, which Insights keys by the function name.
+func facetNumber(facet *newrelic.InsightsFacet, i int, key string) float64 {
+	if i >= len(facet.Results) {
+		return 0
+	}
+	value, _ := facet.Results[i][key].(float64)
+	return value
+}
+
+// queryLocationChecks counts and averages the check results of a
+// monitor in each location, then fetches the latest limit results of
+// every location that reported any.
+func queryLocationChecks(client insightsClient, monitorID string, locations []string, since, limit int) ([]*locationChecks, error) {
+	where := fmt.Sprintf("WHERE monitorId = %s", nrqlString(monitorID))
+	if len(locations) > 0 {
+		where += fmt.Sprintf(" AND location IN (%s)", nrqlStringList(locations))
+	}
+
+	resp, err := client.Query(fmt.Sprintf(
+		"SELECT count(*), filter(count(*), WHERE result = 'SUCCESS'), average(duration) FROM SyntheticCheck %s FACET location SINCE %d MINUTES AGO LIMIT %d",
+		where, since, maxResultLocations,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []*locationChecks
+	for _, facet := range resp.Facets {
+		location := &locationChecks{
+			location:        facet.Name,
+			count:           int(facetNumber(facet, 0, "count")),
+			passed:          int(facetNumber(facet, 1, "count")),
+			averageDuration: facetNumber(facet, 2, "average"),
+		}
+		if location.count == 0 {
+			continue
+		}
+
+		resp, err := client.Query(fmt.Sprintf(
+			"SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = %s AND location = %s SINCE %d MINUTES AGO LIMIT %d",
+			nrqlString(monitorID), nrqlString(facet.Name), since, limit,
+		))
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range resp.Results {
+			events, _ := result["events"].([]interface{})
+			for _, event := range events {
+				if event, ok := event.(map[string]interface{}); ok {
+					location.latest = append(location.latest, checkResultFromEvent(event))
+				}
+			}
+		}
+		sort.Slice(location.latest, func(i, j int) bool {
+			return location.latest[i].timestamp > location.latest[j].timestamp
+		})
+
+		checks = append(checks, location)
+	}
+
+	return checks, nil
+}
+
+// NRSMonitorResultsDataSourceRead queries Insights for the recent check
+// results of a Synthetics monitor. Results are counted and averaged by
+// Insights, and only the latest results of each location are fetched.
+func NRSMonitorResultsDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).insights
+
+	monitorID := resourceData.Get("monitor_id").(string)
+	lastChecks := resourceData.Get("last_checks").(int)
+	limit := lastChecks
+	if limit == 0 {
+		limit = 1
+	}
+
+	checks, err := queryLocationChecks(
+		client,
+		monitorID,
+		util.StrSlice(resourceData.Get("locations").(*schema.Set).List()),
+		resourceData.Get("since_minutes").(int),
+		limit,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error: could not query check results of monitor %s", monitorID)
+	}
+
+	summary := summarizeChecks(checks, lastChecks)
+
+	latest := make([]map[string]interface{}, len(summary.latest))
+	for i, result := range summary.latest {
+		latest[i] = map[string]interface{}{
+			"location":  result.location,
+			"result":    result.result,
+			"duration":  result.duration,
+			"timestamp": int(result.timestamp),
+		}
+	}
+
+	resourceData.SetId(monitorID)
+	if err := resourceData.Set("passing", summary.passing); err != nil {
+		return err
+	}
+	if err := resourceData.Set("passed", summary.passed); err != nil {
+		return err
+	}
+	if err := resourceData.Set("failed", summary.failed); err != nil {
+		return err
+	}
+	if err := resourceData.Set("average_duration", summary.averageDuration); err != nil {
+		return err
+	}
+	if err := resourceData.Set("latest", latest); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSummarizeChecks(t *testing.T) {
+	locations := func() []*locationChecks {
+		return []*locationChecks{
+			{
+				location:        "AWS_US_WEST_1",
+				count:           3,
+				passed:          2,
+				averageDuration: 200,
+				latest: []*checkResult{
+					{location: "AWS_US_WEST_1", result: "SUCCESS", duration: 100, timestamp: 3},
+					{location: "AWS_US_WEST_1", result: "SUCCESS", duration: 200, timestamp: 2},
+					{location: "AWS_US_WEST_1", result: "FAILED", duration: 300, timestamp: 1},
+				},
+			},
+			{
+				location:        "AWS_US_EAST_1",
+				count:           2,
+				passed:          2,
+				averageDuration: 450,
+				latest: []*checkResult{
+					{location: "AWS_US_EAST_1", result: "SUCCESS", duration: 400, timestamp: 2},
+					{location: "AWS_US_EAST_1", result: "SUCCESS", duration: 500, timestamp: 1},
+				},
+			},
+		}
+	}
+
+	summary := summarizeChecks(locations(), 0)
+	if summary.passing {
+		t.Error("expected a failed check in the window to fail")
+	}
+	if summary.passed != 4 || summary.failed != 1 {
+		t.Errorf("expected 4 passed and 1 failed, got %d and %d", summary.passed, summary.failed)
+	}
+	if summary.averageDuration != 300 {
+		t.Errorf("expected an average duration of 300, got %f", summary.averageDuration)
+	}
+	if len(summary.latest) != 2 || summary.latest[0].location != "AWS_US_EAST_1" || summary.latest[1].timestamp != 3 {
+		t.Errorf("unexpected latest results: %+v %+v", summary.latest[0], summary.latest[1])
+	}
+
+	if !summarizeChecks(locations(), 2).passing {
+		t.Error("expected the last 2 checks in every location to pass")
+	}
+	if summarizeChecks(locations(), 3).passing {
+		t.Error("expected too few checks in a location to fail")
+	}
+	if summarizeChecks(nil, 0).passing {
+		t.Error("expected no checks to fail")
+	}
+}
+
+func TestMonitorResultsDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	dataSource := NRSMonitorResultsDataSource()
+
+	client.QueryResults["SELECT count(*), filter(count(*), WHERE result = 'SUCCESS'), average(duration) FROM SyntheticCheck WHERE monitorId = 'abc' AND location IN ('AWS_US_EAST_1', 'AWS_US_WEST_1') FACET location SINCE 30 MINUTES AGO LIMIT 100"] = &newrelic.InsightsResponse{
+		Facets: []*newrelic.InsightsFacet{
+			{
+				Name:    "AWS_US_WEST_1",
+				Results: []map[string]interface{}{{"count": 40.0}, {"count": 38.0}, {"average": 150.0}},
+			},
+			{
+				Name:    "AWS_US_EAST_1",
+				Results: []map[string]interface{}{{"count": 10.0}, {"count": 10.0}, {"average": 400.0}},
+			},
+		},
+	}
+	client.QueryResults["SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = 'abc' AND location = 'AWS_US_WEST_1' SINCE 30 MINUTES AGO LIMIT 2"] = &newrelic.InsightsResponse{
+		Results: []map[string]interface{}{{"events": []interface{}{
+			map[string]interface{}{"location": "AWS_US_WEST_1", "result": "SUCCESS", "duration": 120.0, "timestamp": 2000.0},
+			map[string]interface{}{"location": "AWS_US_WEST_1", "result": "SUCCESS", "duration": 130.0, "timestamp": 1000.0},
+		}}},
+	}
+	client.QueryResults["SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = 'abc' AND location = 'AWS_US_EAST_1' SINCE 30 MINUTES AGO LIMIT 2"] = &newrelic.InsightsResponse{
+		Results: []map[string]interface{}{{"events": []interface{}{
+			map[string]interface{}{"location": "AWS_US_EAST_1", "result": "SUCCESS", "duration": 410.0, "timestamp": 1500.0},
+			map[string]interface{}{"location": "AWS_US_EAST_1", "result": "SUCCESS", "duration": 390.0, "timestamp": 500.0},
+		}}},
+	}
+
+	resourceData := dataSource.Data(nil)
+	for key, value := range map[string]interface{}{
+		"monitor_id":    "abc",
+		"since_minutes": 30,
+		"locations":     []interface{}{"AWS_US_WEST_1", "AWS_US_EAST_1"},
+		"last_checks":   2,
+	} {
+		if err := resourceData.Set(key, value); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := NRSMonitorResultsDataSourceRead(resourceData, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(client.Queries) != 3 {
+		t.Errorf("expected an aggregate query and a query per location, got %q", client.Queries)
+	}
+	for key, expected := range map[string]interface{}{
+		"passing":            true,
+		"passed":             48,
+		"failed":             2,
+		"average_duration":   200.0,
+		"latest.#":           2,
+		"latest.0.location":  "AWS_US_EAST_1",
+		"latest.0.duration":  410.0,
+		"latest.1.location":  "AWS_US_WEST_1",
+		"latest.1.result":    "SUCCESS",
+		"latest.1.timestamp": 2000,
+	} {
+		if value := resourceData.Get(key); value != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, value)
+		}
+	}
+
+	// Without last_checks, one failure in the window fails the monitor
+	// and only the latest result of each location is fetched.
+	client.Queries = nil
+	client.QueryResults["SELECT count(*), filter(count(*), WHERE result = 'SUCCESS'), average(duration) FROM SyntheticCheck WHERE monitorId = 'abc' FACET location SINCE 60 MINUTES AGO LIMIT 100"] = &newrelic.InsightsResponse{
+		Facets: []*newrelic.InsightsFacet{
+			{
+				Name:    "AWS_US_WEST_1",
+				Results: []map[string]interface{}{{"count": 40.0}, {"count": 39.0}, {"average": 150.0}},
+			},
+		},
+	}
+	client.QueryResults["SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = 'abc' AND location = 'AWS_US_WEST_1' SINCE 60 MINUTES AGO LIMIT 1"] = &newrelic.InsightsResponse{
+		Results: []map[string]interface{}{{"events": []interface{}{
+			map[string]interface{}{"location": "AWS_US_WEST_1", "result": "SUCCESS", "duration": 120.0, "timestamp": 2000.0},
+		}}},
+	}
+
+	resourceData = dataSource.Data(nil)
+	for key, value := range map[string]interface{}{"monitor_id": "abc", "since_minutes": 60} {
+		if err := resourceData.Set(key, value); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := NRSMonitorResultsDataSourceRead(resourceData, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(client.Queries) != 2 {
+		t.Errorf("expected an aggregate query and a query per location, got %q", client.Queries)
+	}
+	if resourceData.Get("passing").(bool) || resourceData.Get("failed").(int) != 1 {
+		t.Errorf("expected 1 failed check to fail the monitor, got passing %v and %v failed", resourceData.Get("passing"), resourceData.Get("failed"))
+	}
+}
+
+func TestMonitorResultsDataSourceInsights(t *testing.T) {
+	responses := map[string]string{
+		"SELECT count(*), filter(count(*), WHERE result = 'SUCCESS'), average(duration) FROM SyntheticCheck WHERE monitorId = 'abc' FACET location SINCE 30 MINUTES AGO LIMIT 100": `{
+  "facets": [
+    {"name": "AWS_US_WEST_1", "results": [{"count": 40}, {"count": 39}, {"average": 150.5}]},
+    {"name": "AWS_US_EAST_1", "results": [{"count": 10}, {"count": 10}, {"average": 400}]}
+  ]
+}`,
+		"SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = 'abc' AND location = 'AWS_US_WEST_1' SINCE 30 MINUTES AGO LIMIT 1": `{
+  "results": [{"events": [{"location": "AWS_US_WEST_1", "result": "FAILED", "duration": 30000, "timestamp": 1500000002000}]}]
+}`,
+		"SELECT location, result, duration, timestamp FROM SyntheticCheck WHERE monitorId = 'abc' AND location = 'AWS_US_EAST_1' SINCE 30 MINUTES AGO LIMIT 1": `{
+  "results": [{"events": [{"location": "AWS_US_EAST_1", "result": "SUCCESS", "duration": 410, "timestamp": 1500000001000}]}]
+}`,
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/42/query" || r.Header.Get("X-Query-Key") != "query-key" {
+			http.Error(w, fmt.Sprintf("unexpected request to %s", r.URL.Path), http.StatusForbidden)
+			return
+		}
+		nrql := r.URL.Query().Get("nrql")
+		queries = append(queries, nrql)
+		response, ok := responses[nrql]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unexpected query %q", nrql), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	p := Provider().(*nrsProvider)
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"newrelic_api_key":   "api-key",
+		"account_id":         42,
+		"insights_query_key": "query-key",
+		"insights_base_url":  server.URL + "/v1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resourceData := NRSMonitorResultsDataSource().Data(nil)
+	for key, value := range map[string]interface{}{"monitor_id": "abc", "since_minutes": 30} {
+		if err := resourceData.Set(key, value); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if err := NRSMonitorResultsDataSourceRead(resourceData, p.Meta()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(queries) != 3 {
+		t.Errorf("expected an aggregate query and a query per location, got %q", queries)
+	}
+	for key, expected := range map[string]interface{}{
+		"passing":            false,
+		"passed":             49,
+		"failed":             1,
+		"average_duration":   200.4,
+		"latest.#":           2,
+		"latest.0.location":  "AWS_US_EAST_1",
+		"latest.0.result":    "SUCCESS",
+		"latest.0.timestamp": 1500000001000,
+		"latest.1.location":  "AWS_US_WEST_1",
+		"latest.1.result":    "FAILED",
+		"latest.1.duration":  30000.0,
+	} {
+		if value := resourceData.Get(key); value != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, value)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// NRSMonitorScriptDataSource returns a Terraform schema for reading the
+// script of a scripted Synthetics monitor. It has no script locations:
+// New Relic's script endpoint returns only the script text, and never
+// the private locations a script runs from or their HMACs.
+func NRSMonitorScriptDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"monitor_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the monitor",
+			},
+			"script": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The script text; empty if the monitor has no script. The private locations the script runs from can't be read back from New Relic",
+			},
+			// Named for its encoding, since nrs_monitor keeps the
+			// raw digest of its script in state instead.
+			"sha256_hex": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hex encoded SHA-256 of the script; empty if the monitor has no script",
+			},
+		},
+		Read: NRSMonitorScriptDataSourceRead,
+	}
+}
+
+// NRSMonitorScriptDataSourceRead reads the script of a Synthetics
+// monitor. A monitor without a script reads as an empty script.
+func NRSMonitorScriptDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).synthetics
+
+	monitorID := resourceData.Get("monitor_id").(string)
+	if _, err := client.GetMonitor(monitorID); err != nil {
+		return errors.Wrapf(err, "error: could not get monitor %s", monitorID)
+	}
+
+	script, err := client.GetMonitorScript(monitorID)
+	if err != nil && err != synthetics.ErrMonitorScriptNotFound {
+		return errors.Wrapf(err, "error: could not get script of monitor %s", monitorID)
+	}
+
+	var sum string
+	if script != "" {
+		hash := sha256.Sum256([]byte(script))
+		sum = hex.EncodeToString(hash[:])
+	}
+
+	resourceData.SetId(monitorID)
+	if err := resourceData.Set("script", script); err != nil {
+		return err
+	}
+	if err := resourceData.Set("sha256_hex", sum); err != nil {
+		return err
+	}
+
+	return nil
+}
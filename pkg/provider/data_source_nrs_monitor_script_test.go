@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+)
+
+func TestMonitorScriptDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	dataSource := NRSMonitorScriptDataSource()
+
+	client.Monitors["scripted"] = &synthetics.ExtendedMonitor{ID: "scripted", Type: synthetics.TypeScriptAPI}
+	client.Scripts["scripted"] = &synthetics.UpdateMonitorScriptArgs{ScriptText: "console.log('check')"}
+	client.Monitors["simple"] = &synthetics.ExtendedMonitor{ID: "simple", Type: "SIMPLE"}
+
+	tests := []struct {
+		monitorID string
+		script    string
+		sum       string
+	}{
+		{
+			monitorID: "scripted",
+			script:    "console.log('check')",
+			sum:       "f7754e1e0fb0b63cb4773d7982b0a0c98a7645bde210aa10029380ae2e2e63d0",
+		},
+		{monitorID: "simple"},
+	}
+
+	for _, test := range tests {
+		resourceData := dataSource.Data(nil)
+		if err := resourceData.Set("monitor_id", test.monitorID); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := NRSMonitorScriptDataSourceRead(resourceData, meta); err != nil {
+			t.Fatalf("%s: err: %s", test.monitorID, err)
+		}
+		if script := resourceData.Get("script").(string); script != test.script {
+			t.Errorf("%s: expected script %q, got %q", test.monitorID, test.script, script)
+		}
+		if sum := resourceData.Get("sha256_hex").(string); sum != test.sum {
+			t.Errorf("%s: expected sha256_hex %q, got %q", test.monitorID, test.sum, sum)
+		}
+	}
+
+	resourceData := dataSource.Data(nil)
+	if err := resourceData.Set("monitor_id", "missing"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := NRSMonitorScriptDataSourceRead(resourceData, meta); err == nil {
+		t.Error("expected an error reading the script of a missing monitor")
+	}
+}
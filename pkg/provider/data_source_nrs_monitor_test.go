@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestMonitorDataSource(t *testing.T) {
+	client, meta := newFakeMeta()
+	dataSource := NRSMonitorDataSource()
+
+	client.Monitors["m1"] = &synthetics.ExtendedMonitor{
+		ID:        "m1",
+		Name:      "web",
+		Type:      "SIMPLE",
+		Frequency: 5,
+		URI:       "https://example.com",
+		Locations: []string{"AWS_US_WEST_1"},
+		Status:    "ENABLED",
+	}
+	client.Labels["m1"] = []*newrelic.MonitorLabel{{Category: "Team", Label: "web"}}
+	client.Monitors["m2"] = &synthetics.ExtendedMonitor{ID: "m2", Name: "web-extra", Type: "SIMPLE"}
+	client.Monitors["m3"] = &synthetics.ExtendedMonitor{ID: "m3", Name: "api", Type: "SCRIPT_API"}
+	client.Monitors["m4"] = &synthetics.ExtendedMonitor{ID: "m4", Name: "api", Type: "SCRIPT_API"}
+
+	tests := []struct {
+		config map[string]interface{}
+		id     string
+		err    string
+	}{
+		{config: map[string]interface{}{"monitor_id": "m1"}, id: "m1"},
+		{config: map[string]interface{}{"name": "web"}, id: "m1"},
+		{config: map[string]interface{}{"monitor_id": "m3"}, id: "m3"},
+		{config: map[string]interface{}{"monitor_id": "missing"}, err: "could not get monitor missing"},
+		{config: map[string]interface{}{"name": "we"}, err: `no monitor is named "we"`},
+		{config: map[string]interface{}{"name": "api"}, err: "2 monitors are named \"api\" (m3, m4)"},
+		{config: map[string]interface{}{}, err: "one of monitor_id or name must be set"},
+	}
+
+	for _, test := range tests {
+		resourceData := dataSource.Data(nil)
+		for key, value := range test.config {
+			if err := resourceData.Set(key, value); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+
+		err := NRSMonitorDataSourceRead(resourceData, meta)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: expected an error containing %q, got %v", test.config, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: err: %s", test.config, err)
+		}
+		if resourceData.Id() != test.id || resourceData.Get("monitor_id").(string) != test.id {
+			t.Errorf("%v: expected monitor %s, got %s", test.config, test.id, resourceData.Id())
+		}
+	}
+
+	resourceData := dataSource.Data(nil)
+	if err := resourceData.Set("name", "web"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := NRSMonitorDataSourceRead(resourceData, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state := resourceData.State()
+	checkAttributes(t, state, map[string]string{
+		"name":        "web",
+		"type":        "SIMPLE",
+		"frequency":   "5",
+		"uri":         "https://example.com",
+		"status":      "ENABLED",
+		"locations.#": "1",
+		"labels.Team": "web",
+	})
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// NRSMonitorsDataSource returns a Terraform schema for listing the New
+// Relic Synthetics monitors that match a set of filters.
+func NRSMonitorsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name_regex": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A regular expression that monitor names must match",
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if _, err := regexp.Compile(i.(string)); err != nil {
+						return nil, []error{fmt.Errorf("%s is not a valid regular expression: %s", k, err)}
+					}
+					return nil, nil
+				},
+			},
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The type of monitors to list (one of SIMPLE, BROWSER, SCRIPT_API, SCRIPT_BROWSER)",
+				ValidateFunc: validation.StringInSlice([]string{"SIMPLE", "BROWSER", "SCRIPT_API", "SCRIPT_BROWSER"}, false),
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The status of monitors to list (one of ENABLED, MUTED, DISABLED)",
+				ValidateFunc: validation.StringInSlice([]string{"ENABLED", "MUTED", "DISABLED"}, false),
+			},
+			"location": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A location that monitors must check from",
+			},
+			"ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IDs of the matching monitors, sorted by name",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"names": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of the matching monitors, in the order of ids",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"types": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The types of the matching monitors, in the order of ids",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"statuses": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The statuses of the matching monitors, in the order of ids",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"frequencies": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The checking frequencies of the matching monitors, in the order of ids",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"uris": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The URLs of the matching monitors, in the order of ids",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: NRSMonitorsDataSourceRead,
+	}
+}
+
+// monitorFilter selects monitors. Empty fields match every monitor.
+type monitorFilter struct {
+	nameRegex   *regexp.Regexp
+	monitorType string
+	status      string
+	location    string
+}
+
+func (f *monitorFilter) matches(monitor *synthetics.ExtendedMonitor) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(monitor.Name) {
+		return false
+	}
+	if f.monitorType != "" && monitor.Type != f.monitorType {
+		return false
+	}
+	if f.status != "" && monitor.Status != f.status {
+		return false
+	}
+	if f.location != "" {
+		for _, location := range monitor.Locations {
+			if location == f.location {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// filterMonitors returns the monitors that match filter, sorted by
+// name and then ID so that the result is stable across reads.
+func filterMonitors(monitors []*synthetics.ExtendedMonitor, filter *monitorFilter) []*synthetics.ExtendedMonitor {
+	var matches []*synthetics.ExtendedMonitor
+	for _, monitor := range monitors {
+		if filter.matches(monitor) {
+			matches = append(matches, monitor)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches
+}
+
+// NRSMonitorsDataSourceRead lists every Synthetics monitor and keeps
+// those that match the configured filters.
+func NRSMonitorsDataSourceRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).synthetics
+
+	filter := &monitorFilter{
+		monitorType: resourceData.Get("type").(string),
+		status:      resourceData.Get("status").(string),
+		location:    resourceData.Get("location").(string),
+	}
+	if data, ok := resourceData.GetOk("name_regex"); ok {
+		nameRegex, err := regexp.Compile(data.(string))
+		if err != nil {
+			return errors.Wrap(err, "error: invalid name_regex")
+		}
+		filter.nameRegex = nameRegex
+	}
+
+	monitors, err := getAllMonitors(client)
+	if err != nil {
+		return err
+	}
+	monitors = filterMonitors(monitors, filter)
+
+	var ids, names, types, statuses, uris []string
+	var frequencies []int
+	for _, monitor := range monitors {
+		ids = append(ids, monitor.ID)
+		names = append(names, monitor.Name)
+		types = append(types, monitor.Type)
+		statuses = append(statuses, monitor.Status)
+		frequencies = append(frequencies, int(monitor.Frequency))
+		uris = append(uris, monitor.URI)
+	}
+
+	resourceData.SetId(fmt.Sprintf("%d", hashcode.String(fmt.Sprintf(
+		"%s:%s:%s:%s",
+		resourceData.Get("name_regex").(string), filter.monitorType, filter.status, filter.location,
+	))))
+	if err := resourceData.Set("ids", ids); err != nil {
+		return err
+	}
+	if err := resourceData.Set("names", names); err != nil {
+		return err
+	}
+	if err := resourceData.Set("types", types); err != nil {
+		return err
+	}
+	if err := resourceData.Set("statuses", statuses); err != nil {
+		return err
+	}
+	if err := resourceData.Set("frequencies", frequencies); err != nil {
+		return err
+	}
+	if err := resourceData.Set("uris", uris); err != nil {
+		return err
+	}
+
+	return nil
+}
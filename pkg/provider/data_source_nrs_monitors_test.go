@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+)
+
+func TestFilterMonitors(t *testing.T) {
+	monitors := []*synthetics.ExtendedMonitor{
+		{ID: "1", Name: "prod-checkout", Type: "SIMPLE", Status: "ENABLED", Locations: []string{"AWS_US_WEST_1"}},
+		{ID: "2", Name: "prod-cart", Type: "SCRIPT_BROWSER", Status: "ENABLED", Locations: []string{"AWS_US_EAST_1"}},
+		{ID: "3", Name: "staging-cart", Type: "SCRIPT_BROWSER", Status: "MUTED", Locations: []string{"AWS_US_WEST_1"}},
+	}
+
+	tests := []struct {
+		filter   monitorFilter
+		expected []string
+	}{
+		{filter: monitorFilter{}, expected: []string{"2", "1", "3"}},
+		{filter: monitorFilter{nameRegex: regexp.MustCompile("^prod-")}, expected: []string{"2", "1"}},
+		{filter: monitorFilter{monitorType: "SCRIPT_BROWSER"}, expected: []string{"2", "3"}},
+		{filter: monitorFilter{status: "MUTED"}, expected: []string{"3"}},
+		{filter: monitorFilter{location: "AWS_US_WEST_1", status: "ENABLED"}, expected: []string{"1"}},
+		{filter: monitorFilter{location: "AWS_EU_WEST_1"}, expected: nil},
+	}
+
+	for i, test := range tests {
+		var ids []string
+		for _, monitor := range filterMonitors(monitors, &test.filter) {
+			ids = append(ids, monitor.ID)
+		}
+		if len(ids) != len(test.expected) {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, ids)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != test.expected[j] {
+				t.Errorf("%d: expected %v, got %v", i, test.expected, ids)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/pkg/errors"
+)
+
+// driftResources are the resources Drift checks.
+var driftResources = map[string]func() *schema.Resource{
+	"nrs_monitor":         NRSMonitorResource,
+	"nrs_alert_condition": NRSAlertConditionResource,
+}
+
+// driftIgnored are attributes Drift doesn't compare. A monitor's labels
+// are split from its tags_all by the provider's default_tags, which the
+// state doesn't record, so only tags_all is compared.
+var driftIgnored = map[string]map[string]bool{
+	"nrs_monitor": {"labels": true},
+}
+
+// driftHashed are attributes whose state holds a hash, whose values
+// aren't reported.
+var driftHashed = map[string]map[string]bool{
+	"nrs_monitor": {"script": true},
+}
+
+// DriftReport lists the resources in Terraform state that differ from
+// New Relic.
+type DriftReport struct {
+	Checked   int              `json:"checked"`
+	Resources []*ResourceDrift `json:"resources"`
+}
+
+// ResourceDrift is a resource in Terraform state that differs from New
+// Relic, is missing from New Relic, or couldn't be read.
+type ResourceDrift struct {
+	StateFile  string            `json:"state_file"`
+	Address    string            `json:"address"`
+	ID         string            `json:"id"`
+	Missing    bool              `json:"missing,omitempty"`
+	Attributes []*AttributeDrift `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// AttributeDrift is an attribute whose value in New Relic differs from
+// its value in Terraform state. Attributes are flattened the way the
+// state flattens them, e.g. locations.# for the number of locations.
+// State and Live are empty for attributes stored as hashes, such as a
+// monitor's script.
+type AttributeDrift struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Live  string `json:"live"`
+}
+
+// Drifted returns the number of resources that differ from New Relic,
+// are missing from it and couldn't be read.
+func (r *DriftReport) Drifted() (drifted, missing, failed int) {
+	for _, resource := range r.Resources {
+		switch {
+		case resource.Error != "":
+			failed++
+		case resource.Missing:
+			missing++
+		default:
+			drifted++
+		}
+	}
+
+	return drifted, missing, failed
+}
+
+// WriteText writes the report for people to read.
+func (r *DriftReport) WriteText(w io.Writer) error {
+	for _, resource := range r.Resources {
+		name := fmt.Sprintf("%s (%s) in %s", resource.Address, resource.ID, resource.StateFile)
+		switch {
+		case resource.Error != "":
+			fmt.Fprintf(w, "%s: could not be read: %s\n", name, resource.Error)
+		case resource.Missing:
+			fmt.Fprintf(w, "%s: missing from New Relic\n", name)
+		default:
+			fmt.Fprintf(w, "%s:\n", name)
+			for _, attribute := range resource.Attributes {
+				if attribute.State == "" && attribute.Live == "" {
+					fmt.Fprintf(w, "  %s: changed\n", attribute.Name)
+					continue
+				}
+				fmt.Fprintf(w, "  %s: %q => %q\n", attribute.Name, attribute.State, attribute.Live)
+			}
+		}
+	}
+
+	drifted, missing, failed := r.Drifted()
+	_, err := fmt.Fprintf(w, "Checked %d resources: %d drifted, %d missing, %d could not be read.\n", r.Checked, drifted, missing, failed)
+	return err
+}
+
+// Drift compares the nrs_monitor and nrs_alert_condition resources in
+// Terraform state files with New Relic. Each resource is refreshed the
+// way terraform refresh does, with the resource's own Exists and Read,
+// and the refreshed attributes are compared with the state.
+func Drift(meta interface{}, stateFiles []string) (*DriftReport, error) {
+	report := &DriftReport{Resources: []*ResourceDrift{}}
+	for _, path := range stateFiles {
+		state, err := readStateFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, module := range state.Modules {
+			prefix := ""
+			for _, name := range module.Path[1:] {
+				prefix += "module." + name + "."
+			}
+
+			keys := make([]string, 0, len(module.Resources))
+			for key := range module.Resources {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				resourceState := module.Resources[key]
+				resource, ok := driftResources[resourceState.Type]
+				if !ok || strings.HasPrefix(key, "data.") || resourceState.Primary == nil {
+					continue
+				}
+
+				report.Checked++
+				drift := resourceDrift(resource(), resourceState.Type, resourceState.Primary, meta)
+				if drift == nil {
+					continue
+				}
+				drift.StateFile = path
+				drift.Address = prefix + key
+				report.Resources = append(report.Resources, drift)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// readStateFile reads a Terraform state file.
+func readStateFile(path string) (*terraform.State, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error: could not open state file")
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: could not read state file %s", path)
+	}
+
+	return state, nil
+}
+
+// resourceDrift refreshes a resource and returns how it drifted, or nil
+// if it didn't.
+func resourceDrift(resource *schema.Resource, resourceType string, state *terraform.InstanceState, meta interface{}) *ResourceDrift {
+	drift := &ResourceDrift{ID: state.ID}
+
+	live, err := resource.Refresh(state.DeepCopy(), meta)
+	if err != nil {
+		drift.Error = err.Error()
+		return drift
+	}
+	if live == nil {
+		drift.Missing = true
+		return drift
+	}
+
+	names := map[string]bool{}
+	for name := range state.Attributes {
+		names[name] = true
+	}
+	for name := range live.Attributes {
+		names[name] = true
+	}
+
+	for name := range names {
+		attribute := strings.SplitN(name, ".", 2)[0]
+		if driftIgnored[resourceType][attribute] {
+			continue
+		}
+
+		stateValue, liveValue := state.Attributes[name], stateString(live.Attributes[name])
+		if stateValue == liveValue {
+			continue
+		}
+		if driftHashed[resourceType][attribute] {
+			stateValue, liveValue = "", ""
+		}
+
+		drift.Attributes = append(drift.Attributes, &AttributeDrift{
+			Name:  name,
+			State: stateValue,
+			Live:  liveValue,
+		})
+	}
+	if len(drift.Attributes) == 0 {
+		return nil
+	}
+	sort.Slice(drift.Attributes, func(i, j int) bool { return drift.Attributes[i].Name < drift.Attributes[j].Name })
+
+	return drift
+}
+
+// stateString returns a value the way it reads back from a state file.
+// State files are JSON, so invalid UTF-8, e.g. in a raw hash, is
+// replaced.
+func stateString(s string) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	var decoded string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return s
+	}
+
+	return decoded
+}
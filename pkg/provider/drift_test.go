@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// writeStateFile writes a state file with resources in the root module
+// and the module named child.
+func writeStateFile(t *testing.T, path string, root, child map[string]*terraform.ResourceState) {
+	state := terraform.NewState()
+	state.RootModule().Resources = root
+	state.AddModule([]string{"root", "child"}).Resources = child
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	if err := terraform.WriteState(state, f); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	client, meta := newFakeMeta()
+
+	monitorState := applyResource(t, NRSMonitorResource(), nil, map[string]interface{}{
+		"name":      "api",
+		"type":      "SCRIPT_API",
+		"frequency": 5,
+		"locations": []interface{}{"AWS_US_WEST_1"},
+		"status":    "ENABLED",
+		"script":    "console.log('ok')",
+		"labels":    map[string]interface{}{"Team": "api"},
+	}, meta)
+	unchangedState := applyResource(t, NRSMonitorResource(), nil, simpleMonitorConfig(), meta)
+	deletedState := applyResource(t, NRSMonitorResource(), nil, simpleMonitorConfig(), meta)
+	conditionState := applyResource(t, NRSAlertConditionResource(), nil, map[string]interface{}{
+		"name":       "api down",
+		"monitor_id": monitorState.ID,
+		"policy_id":  7,
+		"enabled":    true,
+	}, meta)
+
+	dir, err := ioutil.TempDir("", "drift")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "terraform.tfstate")
+	writeStateFile(t, path, map[string]*terraform.ResourceState{
+		"nrs_monitor.api":       {Type: "nrs_monitor", Primary: monitorState},
+		"nrs_monitor.unchanged": {Type: "nrs_monitor", Primary: unchangedState},
+		"data.nrs_monitor.api":  {Type: "nrs_monitor", Primary: monitorState},
+	}, map[string]*terraform.ResourceState{
+		"nrs_monitor.deleted":         {Type: "nrs_monitor", Primary: deletedState},
+		"nrs_alert_condition.api":     {Type: "nrs_alert_condition", Primary: conditionState},
+		"nrs_multi_location_alert.ok": {Type: "nrs_multi_location_alert_condition", Primary: conditionState},
+	})
+
+	report, err := Drift(meta, []string{path})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if report.Checked != 4 || len(report.Resources) != 0 {
+		t.Fatalf("expected no drift in 4 resources, got %d resources and %+v", report.Checked, report.Resources)
+	}
+
+	client.Monitors[monitorState.ID].Frequency = 15
+	client.Scripts[monitorState.ID] = &synthetics.UpdateMonitorScriptArgs{ScriptText: "console.log('changed')"}
+	client.Labels[monitorState.ID][0].Label = "web"
+	conditionID, err := strconv.ParseUint(conditionState.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.AlertConditions[uint(conditionID)].Enabled = false
+	delete(client.Monitors, deletedState.ID)
+
+	report, err = Drift(meta, []string{path})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(report.Resources) != 3 {
+		t.Fatalf("expected 3 drifted resources, got %+v", report.Resources)
+	}
+
+	condition := report.Resources[1]
+	if condition.Address != "module.child.nrs_alert_condition.api" || len(condition.Attributes) != 1 || *condition.Attributes[0] != (AttributeDrift{Name: "enabled", State: "true", Live: "false"}) {
+		t.Errorf("unexpected alert condition drift %+v", condition)
+	}
+
+	deleted := report.Resources[2]
+	if deleted.Address != "module.child.nrs_monitor.deleted" || !deleted.Missing || deleted.ID != deletedState.ID {
+		t.Errorf("expected the deleted monitor to be missing, got %+v", deleted)
+	}
+
+	monitor := report.Resources[0]
+	if monitor.Address != "nrs_monitor.api" || monitor.StateFile != path {
+		t.Errorf("unexpected monitor drift %+v", monitor)
+	}
+	expected := []AttributeDrift{
+		{Name: "frequency", State: "5", Live: "15"},
+		{Name: "script"},
+		{Name: "tags_all.Team", State: "api", Live: "web"},
+	}
+	if len(monitor.Attributes) != len(expected) {
+		t.Fatalf("expected %d drifted attributes, got %+v", len(expected), monitor.Attributes)
+	}
+	for i, attribute := range monitor.Attributes {
+		if *attribute != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], *attribute)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(buf.String(), "nrs_monitor.api ("+monitorState.ID+") in "+path+":\n  frequency: \"5\" => \"15\"\n  script: changed\n") {
+		t.Errorf("expected the report to show the drifted monitor, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "module.child.nrs_monitor.deleted ("+deletedState.ID+") in "+path+": missing from New Relic\n") {
+		t.Errorf("expected the report to show the missing monitor, got:\n%s", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), "Checked 4 resources: 2 drifted, 1 missing, 0 could not be read.\n") {
+		t.Errorf("unexpected report summary:\n%s", buf.String())
+	}
+}
+
+func TestDriftReadError(t *testing.T) {
+	client, meta := newFakeMeta()
+	state := applyResource(t, NRSMonitorResource(), nil, simpleMonitorConfig(), meta)
+
+	dir, err := ioutil.TempDir("", "drift")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "terraform.tfstate")
+	writeStateFile(t, path, map[string]*terraform.ResourceState{
+		"nrs_monitor.monitor": {Type: "nrs_monitor", Primary: state},
+	}, nil)
+
+	client.Errors["GetMonitorLabels"] = errors.New("injected")
+	report, err := Drift(meta, []string{path})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(report.Resources) != 1 || report.Resources[0].Error == "" {
+		t.Errorf("expected the monitor to fail to read, got %+v", report.Resources)
+	}
+
+	if _, err := Drift(meta, []string{filepath.Join(dir, "missing.tfstate")}); err == nil {
+		t.Error("expected an error for a missing state file")
+	}
+}
@@ -0,0 +1,303 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// Files written by Export.
+const (
+	ExportMonitorsFile        = "nrs_monitors.tf"
+	ExportAlertConditionsFile = "nrs_alert_conditions.tf"
+	ExportImportScript        = "nrs_import.sh"
+	ExportScriptsDir          = "scripts"
+)
+
+const exportHeader = "# Generated by terraform-provider-nrs export.\n"
+
+// Export writes Terraform configuration for every Synthetics monitor
+// and alert condition in New Relic to dir: an nrs_monitor for each
+// monitor in ExportMonitorsFile, the script of each scripted monitor in
+// ExportScriptsDir, an nrs_alert_condition for each Synthetics alert
+// condition in ExportAlertConditionsFile, and the terraform import
+// commands that bring them all into state in ExportImportScript. It
+// returns the number of monitors and alert conditions exported.
+func Export(meta interface{}, dir string) (int, int, error) {
+	m := meta.(*providerMeta)
+
+	monitors, err := getAllMonitors(m.synthetics)
+	if err != nil {
+		return 0, 0, err
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		if monitors[i].Name != monitors[j].Name {
+			return monitors[i].Name < monitors[j].Name
+		}
+		return monitors[i].ID < monitors[j].ID
+	})
+
+	imports := bytes.NewBufferString("#!/bin/sh\n" + exportHeader + "set -e\n\n")
+
+	monitorsTF := bytes.NewBufferString(exportHeader)
+	monitorNames := resourceNames{}
+	monitorResources := map[string]string{}
+	for _, monitor := range monitors {
+		name := monitorNames.add(monitor.Name, "monitor")
+		monitorResources[monitor.ID] = name
+
+		attributes, script, err := exportMonitor(m, monitor, name)
+		if err != nil {
+			return 0, 0, err
+		}
+		if script != nil {
+			if err := writeExportFile(dir, filepath.Join(ExportScriptsDir, name+".js"), []byte(*script), 0644); err != nil {
+				return 0, 0, err
+			}
+		}
+
+		writeHCLResource(monitorsTF, "nrs_monitor", name, attributes)
+		fmt.Fprintf(imports, "terraform import nrs_monitor.%s %s\n", name, monitor.ID)
+	}
+
+	policies, err := m.alertPolicies.GetAlertPolicies("")
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "error: could not list alert policies")
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+
+	conditionsTF := bytes.NewBufferString(exportHeader)
+	conditionNames := resourceNames{}
+	conditionCount := 0
+	for _, policy := range policies {
+		conditions, err := m.alertPolicies.GetSyntheticsConditions(policy.ID)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "error: could not list alert conditions in policy %d", policy.ID)
+		}
+		sort.Slice(conditions, func(i, j int) bool {
+			if conditions[i].Name != conditions[j].Name {
+				return conditions[i].Name < conditions[j].Name
+			}
+			return conditions[i].ID < conditions[j].ID
+		})
+
+		for _, condition := range conditions {
+			name := conditionNames.add(condition.Name, "condition")
+
+			// Refer to exported monitors, so that Terraform creates
+			// and replaces them in the right order.
+			monitorID := hclString(condition.MonitorID)
+			if monitor, ok := monitorResources[condition.MonitorID]; ok {
+				monitorID = fmt.Sprintf(`"${nrs_monitor.%s.id}"`, monitor)
+			}
+
+			attributes := []hclAttribute{
+				{"name", hclString(condition.Name)},
+				{"monitor_id", monitorID},
+				{"policy_id", fmt.Sprintf("%d", policy.ID)},
+				{"enabled", fmt.Sprintf("%t", condition.Enabled)},
+			}
+			if condition.RunbookURL != "" {
+				attributes = append(attributes, hclAttribute{"runbook_url", hclString(condition.RunbookURL)})
+			}
+
+			writeHCLResource(conditionsTF, "nrs_alert_condition", name, attributes)
+			fmt.Fprintf(imports, "terraform import nrs_alert_condition.%s %d:%d\n", name, policy.ID, condition.ID)
+			conditionCount++
+		}
+	}
+
+	if err := writeExportFile(dir, ExportMonitorsFile, monitorsTF.Bytes(), 0644); err != nil {
+		return 0, 0, err
+	}
+	if err := writeExportFile(dir, ExportAlertConditionsFile, conditionsTF.Bytes(), 0644); err != nil {
+		return 0, 0, err
+	}
+	if err := writeExportFile(dir, ExportImportScript, imports.Bytes(), 0755); err != nil {
+		return 0, 0, err
+	}
+
+	return len(monitors), conditionCount, nil
+}
+
+// exportMonitor returns the nrs_monitor arguments of a monitor, read
+// the way NRSMonitorRead reads them, and the monitor's script if it has
+// one.
+func exportMonitor(meta *providerMeta, monitor *synthetics.ExtendedMonitor, name string) ([]hclAttribute, *string, error) {
+	resourceData := NRSMonitorResource().Data(nil)
+	resourceData.SetId(monitor.ID)
+	if err := readMonitor(resourceData, meta, monitor); err != nil {
+		return nil, nil, errors.Wrapf(err, "error: could not read monitor %s", monitor.ID)
+	}
+
+	attributes := []hclAttribute{
+		{"name", hclString(resourceData.Get("name").(string))},
+		{"type", hclString(resourceData.Get("type").(string))},
+		{"frequency", fmt.Sprintf("%d", resourceData.Get("frequency").(int))},
+		{"status", hclString(resourceData.Get("status").(string))},
+		{"locations", hclList(util.StrSlice(resourceData.Get("locations").(*schema.Set).List()))},
+	}
+	if uri := resourceData.Get("uri").(string); uri != "" {
+		attributes = append(attributes, hclAttribute{"uri", hclString(uri)})
+	}
+	if sla := resourceData.Get("sla_threshold").(float64); sla != 0 {
+		attributes = append(attributes, hclAttribute{"sla_threshold", strconv.FormatFloat(sla, 'f', -1, 64)})
+	}
+	if validationString := resourceData.Get("validation_string").(string); validationString != "" {
+		attributes = append(attributes, hclAttribute{"validation_string", hclString(validationString)})
+	}
+
+	// Options New Relic returns are exported even when false, since
+	// leaving them out of configuration would unset them.
+	for _, option := range []struct {
+		attribute string
+		value     *bool
+	}{
+		{"verify_ssl", monitor.VerifySSL},
+		{"bypass_head_request", monitor.BypassHEADRequest},
+		{"treat_redirect_as_failure", monitor.TreatRedirectAsFailure},
+	} {
+		if option.value != nil {
+			attributes = append(attributes, hclAttribute{option.attribute, fmt.Sprintf("%t", *option.value)})
+		}
+	}
+
+	// The state only holds a hash of the script, so the script is
+	// fetched again.
+	var script *string
+	if resourceData.Get("script").(string) != "" {
+		text, err := meta.synthetics.GetMonitorScript(monitor.ID)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "error: could not get script of monitor %s", monitor.ID)
+		}
+		script = &text
+		attributes = append(attributes, hclAttribute{
+			"script",
+			fmt.Sprintf(`"${file("${path.module}/%s/%s.js")}"`, ExportScriptsDir, name),
+		})
+	}
+
+	if labels := resourceData.Get("labels").(map[string]interface{}); len(labels) > 0 {
+		attributes = append(attributes, hclAttribute{"labels", hclMap(labels)})
+	}
+
+	return attributes, script, nil
+}
+
+// writeExportFile writes a file below dir, creating its directory.
+func writeExportFile(dir, name string, data []byte, perm os.FileMode) error {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "error: could not create %s", filepath.Dir(path))
+	}
+	if err := ioutil.WriteFile(path, data, perm); err != nil {
+		return errors.Wrapf(err, "error: could not write %s", path)
+	}
+
+	return nil
+}
+
+// nonIdentifier matches the characters that can't be in a Terraform
+// resource name.
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceNames hands out unique Terraform resource names.
+type resourceNames map[string]bool
+
+// add returns a resource name derived from name that hasn't been handed
+// out yet. Names that don't start with a letter are prefixed with
+// fallback.
+func (r resourceNames) add(name, fallback string) string {
+	base := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" {
+		base = fallback
+	} else if base[0] < 'a' || base[0] > 'z' {
+		base = fallback + "_" + base
+	}
+
+	unique := base
+	for i := 2; r[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", base, i)
+	}
+	r[unique] = true
+
+	return unique
+}
+
+// hclAttribute is an argument of a resource, with its value in HCL.
+type hclAttribute struct {
+	name, value string
+}
+
+// writeHCLResource writes a resource block, formatted the way
+// terraform fmt formats it.
+func writeHCLResource(buf *bytes.Buffer, resourceType, name string, attributes []hclAttribute) {
+	width := 0
+	for _, attribute := range attributes {
+		if !strings.Contains(attribute.value, "\n") && len(attribute.name) > width {
+			width = len(attribute.name)
+		}
+	}
+
+	fmt.Fprintf(buf, "\nresource %q %q {\n", resourceType, name)
+	for _, attribute := range attributes {
+		if strings.Contains(attribute.value, "\n") {
+			fmt.Fprintf(buf, "\n  %s = %s\n", attribute.name, attribute.value)
+			continue
+		}
+		fmt.Fprintf(buf, "  %-*s = %s\n", width, attribute.name, attribute.value)
+	}
+	buf.WriteString("}\n")
+}
+
+// hclString returns s as an HCL string, with interpolations escaped.
+func hclString(s string) string {
+	return strconv.Quote(strings.Replace(s, "${", "$${", -1))
+}
+
+// hclList returns a sorted list of strings in HCL.
+func hclList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	quoted := make([]string, len(sorted))
+	for i, value := range sorted {
+		quoted[i] = hclString(value)
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclMap returns a map of strings in HCL, as the value of a resource
+// argument.
+func hclMap(m map[string]interface{}) string {
+	keys := make([]string, 0, len(m))
+	width := 0
+	for key := range m {
+		keys = append(keys, key)
+		if len(hclString(key)) > width {
+			width = len(hclString(key))
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "    %-*s = %s\n", width, hclString(key), hclString(m[key].(string)))
+	}
+	buf.WriteString("  }")
+
+	return buf.String()
+}
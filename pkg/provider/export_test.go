@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestExport(t *testing.T) {
+	client, meta := newFakeMeta()
+	client.Monitors["m1"] = &synthetics.ExtendedMonitor{
+		ID:           "m1",
+		Name:         "Web home",
+		Type:         "SIMPLE",
+		Frequency:    5,
+		URI:          "https://example.com",
+		Locations:    []string{"AWS_US_WEST_1", "AWS_US_EAST_1"},
+		Status:       "ENABLED",
+		SLAThreshold: 7,
+		VerifySSL:    util.BoolPtr(false),
+	}
+	client.Labels["m1"] = []*newrelic.MonitorLabel{{Category: "Team", Label: "web"}}
+	client.Monitors["m2"] = &synthetics.ExtendedMonitor{
+		ID:        "m2",
+		Name:      "Web home",
+		Type:      "SIMPLE",
+		Frequency: 60,
+		URI:       "https://example.com/${path}",
+		Locations: []string{"AWS_US_WEST_1"},
+		Status:    "DISABLED",
+	}
+	client.Monitors["m3"] = &synthetics.ExtendedMonitor{
+		ID:        "m3",
+		Name:      "1 API check",
+		Type:      "SCRIPT_API",
+		Frequency: 15,
+		Locations: []string{"AWS_US_WEST_1"},
+		Status:    "MUTED",
+	}
+	client.Scripts["m3"] = &synthetics.UpdateMonitorScriptArgs{ScriptText: "console.log('ok')"}
+	client.Policies[7] = &newrelic.AlertPolicy{ID: 7, Name: "On call"}
+	client.AlertConditions[10] = &synthetics.AlertCondition{ID: 10, Name: "Web home down", MonitorID: "m1", RunbookURL: "https://example.com/runbook", Enabled: true}
+	client.AlertConditionPolicies[10] = 7
+	client.AlertConditions[11] = &synthetics.AlertCondition{ID: 11, Name: "Gone", MonitorID: "missing"}
+	client.AlertConditionPolicies[11] = 7
+
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	monitors, conditions, err := Export(meta, dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if monitors != 3 || conditions != 2 {
+		t.Errorf("expected 3 monitors and 2 alert conditions, got %d and %d", monitors, conditions)
+	}
+
+	expected := map[string]string{
+		ExportMonitorsFile: `# Generated by terraform-provider-nrs export.
+
+resource "nrs_monitor" "monitor_1_api_check" {
+  name      = "1 API check"
+  type      = "SCRIPT_API"
+  frequency = 15
+  status    = "MUTED"
+  locations = ["AWS_US_WEST_1"]
+  script    = "${file("${path.module}/scripts/monitor_1_api_check.js")}"
+}
+
+resource "nrs_monitor" "web_home" {
+  name          = "Web home"
+  type          = "SIMPLE"
+  frequency     = 5
+  status        = "ENABLED"
+  locations     = ["AWS_US_EAST_1", "AWS_US_WEST_1"]
+  uri           = "https://example.com"
+  sla_threshold = 7
+  verify_ssl    = false
+
+  labels = {
+    "Team" = "web"
+  }
+}
+
+resource "nrs_monitor" "web_home_2" {
+  name      = "Web home"
+  type      = "SIMPLE"
+  frequency = 60
+  status    = "DISABLED"
+  locations = ["AWS_US_WEST_1"]
+  uri       = "https://example.com/$${path}"
+}
+`,
+		ExportAlertConditionsFile: `# Generated by terraform-provider-nrs export.
+
+resource "nrs_alert_condition" "gone" {
+  name       = "Gone"
+  monitor_id = "missing"
+  policy_id  = 7
+  enabled    = false
+}
+
+resource "nrs_alert_condition" "web_home_down" {
+  name        = "Web home down"
+  monitor_id  = "${nrs_monitor.web_home.id}"
+  policy_id   = 7
+  enabled     = true
+  runbook_url = "https://example.com/runbook"
+}
+`,
+		ExportImportScript: `#!/bin/sh
+# Generated by terraform-provider-nrs export.
+set -e
+
+terraform import nrs_monitor.monitor_1_api_check m3
+terraform import nrs_monitor.web_home m1
+terraform import nrs_monitor.web_home_2 m2
+terraform import nrs_alert_condition.gone 7:11
+terraform import nrs_alert_condition.web_home_down 7:10
+`,
+		filepath.Join(ExportScriptsDir, "monitor_1_api_check.js"): "console.log('ok')",
+	}
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(data) != content {
+			t.Errorf("expected %s to be:\n%s\ngot:\n%s", name, content, data)
+		}
+	}
+
+	for name, count := range map[string]int{ExportMonitorsFile: 3, ExportAlertConditionsFile: 2} {
+		c, err := config.LoadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be valid configuration: %s", name, err)
+		}
+		if len(c.Resources) != count {
+			t.Errorf("expected %d resources in %s, got %d", count, name, len(c.Resources))
+		}
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	for _, method := range []string{"GetAllMonitors", "GetMonitorScript", "GetMonitorLabels", "GetAlertPolicies", "GetSyntheticsConditions"} {
+		client, meta := newFakeMeta()
+		client.Monitors["m1"] = &synthetics.ExtendedMonitor{ID: "m1", Name: "check", Type: "SCRIPT_API"}
+		client.Scripts["m1"] = &synthetics.UpdateMonitorScriptArgs{ScriptText: "console.log('ok')"}
+		client.Policies[7] = &newrelic.AlertPolicy{ID: 7, Name: "On call"}
+		client.Errors[method] = errors.New("injected")
+
+		dir, err := ioutil.TempDir("", "export")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if _, _, err := Export(meta, dir); err == nil {
+			t.Errorf("expected an error when %s fails", method)
+		}
+	}
+}
+
+func TestExportConditionPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/alerts_policies.json":
+			w.Write([]byte(`{"policies": [{"id": 7, "name": "On call"}]}`))
+		case r.URL.Path == "/alerts_synthetics_conditions.json" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", `</alerts_synthetics_conditions.json?policy_id=7&page=2>; rel="next"`)
+			w.Write([]byte(`{"synthetics_conditions": [{"id": 10, "name": "Web home down", "monitor_id": "m1"}]}`))
+		case r.URL.Path == "/alerts_synthetics_conditions.json" && r.URL.Query().Get("page") == "2":
+			w.Write([]byte(`{"synthetics_conditions": [{"id": 11, "name": "Web home slow", "monitor_id": "m1"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newrelicClient, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = "key"
+		c.AlertsBaseURL = server.URL
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, meta := newFakeMeta()
+	meta.alertPolicies = newrelicClient
+
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, conditions, err := Export(meta, dir); err != nil || conditions != 2 {
+		t.Fatalf("expected the alert conditions of both pages, got %d (err: %v)", conditions, err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, ExportImportScript))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, line := range []string{
+		"terraform import nrs_alert_condition.web_home_down 7:10\n",
+		"terraform import nrs_alert_condition.web_home_slow 7:11\n",
+	} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("expected %q in the import script, got:\n%s", line, data)
+		}
+	}
+}
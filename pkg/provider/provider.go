@@ -1,15 +1,26 @@
 package provider
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
 	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/pkg/errors"
 )
 
 // Provider returns a new New Relic Synthetics Terraform provider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	return &nrsProvider{Provider: &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"newrelic_api_key": &schema.Schema{
 				Type:        schema.TypeString,
@@ -18,13 +29,418 @@ func Provider() terraform.ResourceProvider {
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_API_KEY", "key"),
 			},
+			"account_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The New Relic account ID, needed to query check results",
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_ACCOUNT_ID", nil),
+			},
+			"insights_query_key": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An Insights query key for New Relic, needed to query check results",
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_QUERY_KEY", nil),
+			},
+			"synthetics_base_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The base URL of the Synthetics API, to use a stand-in for New Relic",
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_SYNTHETICS_BASE_URL", newrelic.DefaultSyntheticsBaseURL),
+				ValidateFunc: validateBaseURL,
+			},
+			"alerts_base_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The base URL of the Alerts API, to use a stand-in for New Relic",
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_ALERTS_BASE_URL", newrelic.DefaultAlertsBaseURL),
+				ValidateFunc: validateBaseURL,
+			},
+			"insights_base_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The base URL of the Insights query API, to use a stand-in for New Relic",
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_BASE_URL", newrelic.DefaultInsightsBaseURL),
+				ValidateFunc: validateBaseURL,
+			},
+			"read_only": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Refuse every request that would change anything in New Relic",
+			},
+			"default_locations": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The locations of monitors that don't set locations",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"default_frequency": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The checking frequency in minutes of monitors that don't set frequency",
+			},
+			"default_status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The status of monitors that don't set status (one of ENABLED, MUTED, DISABLED)",
+				ValidateFunc: validation.StringInSlice([]string{"ENABLED", "MUTED", "DISABLED"}, false),
+			},
+			"name_policy": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The naming convention for monitors and alert conditions",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"pattern": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "A regular expression names must match, including the prefix",
+							ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+								if _, err := regexp.Compile(i.(string)); err != nil {
+									return nil, []error{fmt.Errorf("%s is not a valid regular expression: %s", k, err)}
+								}
+								return nil, nil
+							},
+						},
+						"prefix": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "A prefix prepended to names that don't start with it",
+						},
+					},
+				},
+			},
+			"adopt_existing": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether monitors that don't set adopt_existing take over existing monitors with the same name and type. Monitors managed by another resource are only detected when both are created in the same run",
+			},
+			"default_tags": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Labels added to every monitor, as a map of category to label",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 		ConfigureFunc: getClient,
+		DataSourcesMap: map[string]*schema.Resource{
+			"nrs_alert_condition": NRSAlertConditionDataSource(),
+			"nrs_alert_policy":    NRSAlertPolicyDataSource(),
+			"nrs_locations":       NRSLocationsDataSource(),
+			"nrs_monitor":         NRSMonitorDataSource(),
+			"nrs_monitor_results": NRSMonitorResultsDataSource(),
+			"nrs_monitor_script":  NRSMonitorScriptDataSource(),
+			"nrs_monitors":        NRSMonitorsDataSource(),
+		},
 		ResourcesMap: map[string]*schema.Resource{
-			"nrs_monitor":         NRSMonitorResource(),
-			"nrs_alert_condition": NRSAlertConditionResource(),
+			"nrs_monitor":                        NRSMonitorResource(),
+			"nrs_alert_condition":                NRSAlertConditionResource(),
+			"nrs_multi_location_alert_condition": NRSMultiLocationAlertConditionResource(),
+			"nrs_synthetics_nrql_condition":      NRSSyntheticsNRQLConditionResource(),
 		},
+	}}
+}
+
+// ReadOnlyMeta configures the provider from the environment in
+// read_only mode and returns the meta argument its resource functions
+// get, for commands that read New Relic outside of Terraform.
+func ReadOnlyMeta() (interface{}, error) {
+	p := Provider().(*nrsProvider)
+
+	raw, err := config.NewRawConfig(map[string]interface{}{"read_only": true})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		return nil, errors.Wrap(err, "error: could not configure provider")
+	}
+
+	return p.Meta(), nil
+}
+
+// nrsProvider adds the provider's defaults and name policy to the
+// configuration of resources, so that plans show the values a resource
+// will end up with. helper/schema has no hook for attributes computed
+// from provider configuration at plan time.
+type nrsProvider struct {
+	*schema.Provider
+}
+
+// monitorDefaults maps nrs_monitor attributes to the provider
+// attributes that default them.
+var monitorDefaults = []struct {
+	attribute, providerAttribute string
+}{
+	{"locations", "default_locations"},
+	{"frequency", "default_frequency"},
+	{"status", "default_status"},
+}
+
+// namePolicyResources are the resources whose names must follow the
+// provider's name_policy.
+var namePolicyResources = map[string]bool{
+	"nrs_monitor":         true,
+	"nrs_alert_condition": true,
+}
+
+// ValidateResource validates a resource configuration. Once the
+// provider is configured, it also checks names against the name policy
+// and that every nrs_monitor argument with a provider default is set
+// one way or the other.
+func (p *nrsProvider) ValidateResource(t string, c *terraform.ResourceConfig) ([]string, []error) {
+	warns, errs := p.Provider.ValidateResource(t, c)
+
+	meta, configured := p.Meta().(*providerMeta)
+	if configured && namePolicyResources[t] && meta.namePolicy != nil && !c.IsComputed("name") {
+		if name, ok := c.Get("name"); ok {
+			if err := meta.namePolicy.validate(name.(string)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if t != "nrs_monitor" {
+		return warns, errs
+	}
+
+	if _, ok := c.Raw["tags_all"]; ok {
+		errs = append(errs, errors.New("tags_all is computed from labels and the provider's default_tags and can't be set"))
+	}
+
+	if configured {
+		defaults := meta.monitorDefaults()
+		for _, d := range monitorDefaults {
+			_, set := c.Raw[d.attribute]
+			if _, ok := defaults[d.attribute]; !set && !ok {
+				errs = append(errs, fmt.Errorf("%s must be set, or %s on the provider", d.attribute, d.providerAttribute))
+			}
+		}
+	}
+
+	return warns, errs
+}
+
+// deletionProtectionResources are the resources with a
+// deletion_protection argument.
+var deletionProtectionResources = map[string]bool{
+	"nrs_monitor":         true,
+	"nrs_alert_condition": true,
+}
+
+// Diff diffs a resource (see diff), leaving out deletion_protection
+// for resources whose state doesn't have it yet.
+func (p *nrsProvider) Diff(info *terraform.InstanceInfo, s *terraform.InstanceState, c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	diff, err := p.diff(info, s, c)
+	if err != nil || diff == nil {
+		return diff, err
+	}
+
+	// State saved before deletion_protection was added doesn't have
+	// it, and schema diffs the missing value against the default.
+	if deletionProtectionResources[info.Type] && s != nil && s.ID != "" {
+		_, inState := s.Attributes["deletion_protection"]
+		if attr, ok := diff.Attributes["deletion_protection"]; ok && !inState && attr.Old == "" && attr.New == "false" {
+			delete(diff.Attributes, "deletion_protection")
+			if diff.Empty() {
+				return nil, nil
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// diff prefixes names according to the name policy, fills in the
+// provider's defaults for unset nrs_monitor arguments (including
+// adopt_existing) and sets tags_all
+// to the monitor's labels merged with the provider's default tags
+// before diffing.
+func (p *nrsProvider) diff(info *terraform.InstanceInfo, s *terraform.InstanceState, c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	meta, ok := p.Meta().(*providerMeta)
+	if !ok {
+		return p.Provider.Diff(info, s, c)
+	}
+
+	applyNamePolicy := namePolicyResources[info.Type] && meta.namePolicy != nil && !c.IsComputed("name")
+	if !applyNamePolicy && info.Type != "nrs_monitor" {
+		return p.Provider.Diff(info, s, c)
+	}
+
+	reader := &schema.ConfigFieldReader{
+		Config: c,
+		Schema: p.ResourcesMap[info.Type].Schema,
+	}
+
+	c = c.DeepCopy()
+	if c.Raw == nil {
+		c.Raw = map[string]interface{}{}
+	}
+	if c.Config == nil {
+		c.Config = map[string]interface{}{}
+	}
+
+	if applyNamePolicy {
+		if name, ok := c.Get("name"); ok {
+			c.Raw["name"] = meta.namePolicy.apply(name.(string))
+			c.Config["name"] = c.Raw["name"]
+		}
+	}
+
+	if info.Type != "nrs_monitor" {
+		return p.Provider.Diff(info, s, c)
 	}
+
+	result, err := reader.ReadField([]string{"labels"})
+	if err != nil {
+		return nil, err
+	}
+
+	for attribute, value := range meta.monitorDefaults() {
+		if _, ok := c.Raw[attribute]; !ok {
+			c.Raw[attribute] = value
+			c.Config[attribute] = value
+		}
+	}
+	if _, ok := c.Raw["adopt_existing"]; !ok && meta.adoptExisting {
+		c.Raw["adopt_existing"] = true
+		c.Config["adopt_existing"] = true
+	}
+
+	var tags map[string]interface{}
+	if result.Computed {
+		c.Raw["tags_all"] = "${labels}"
+		c.Config["tags_all"] = config.UnknownVariableValue
+		c.ComputedKeys = append(c.ComputedKeys, "tags_all")
+	} else {
+		labels, _ := result.Value.(map[string]interface{})
+		tags = mergeLabels(meta.defaultTags, labels)
+		c.Raw["tags_all"] = tags
+		c.Config["tags_all"] = tags
+	}
+
+	diff, err := p.Provider.Diff(info, s, c)
+	if err != nil || diff == nil {
+		return diff, err
+	}
+
+	// The state doesn't keep an empty tags_all, so schema diffs it as
+	// computed again on every plan for a monitor without labels.
+	if attr, ok := diff.Attributes["tags_all.%"]; ok && attr.NewComputed && !result.Computed && len(tags) == 0 && s != nil && s.ID != "" {
+		delete(diff.Attributes, "tags_all.%")
+		if diff.Empty() {
+			return nil, nil
+		}
+	}
+
+	return diff, nil
+}
+
+// validateBaseURL checks that a base URL is an absolute URL.
+func validateBaseURL(i interface{}, k string) ([]string, []error) {
+	u, err := url.Parse(i.(string))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, []error{fmt.Errorf("%s must be an absolute URL, got %q", k, i.(string))}
+	}
+
+	return nil, nil
+}
+
+// namePolicy is the naming convention resource names must follow.
+type namePolicy struct {
+	pattern *regexp.Regexp
+	prefix  string
+}
+
+// apply prepends the policy's prefix to a name that doesn't start with
+// it already.
+func (n *namePolicy) apply(name string) string {
+	if strings.HasPrefix(name, n.prefix) {
+		return name
+	}
+
+	return n.prefix + name
+}
+
+// validate checks a name, after applying the prefix, against the
+// policy's pattern.
+func (n *namePolicy) validate(name string) error {
+	full := n.apply(name)
+	if n.pattern.MatchString(full) {
+		return nil
+	}
+
+	if full != name {
+		return fmt.Errorf("name %q (%q with the name_policy prefix) must match the provider's name_policy pattern %q", name, full, n.pattern.String())
+	}
+
+	return fmt.Errorf("name %q must match the provider's name_policy pattern %q", name, n.pattern.String())
+}
+
+// providerMeta is passed to every resource function as its meta
+// argument.
+type providerMeta struct {
+	synthetics                syntheticsClient
+	monitorLabels             monitorLabelClient
+	alertPolicies             alertPolicyClient
+	locationFailureConditions locationFailureConditionClient
+	nrqlConditions            nrqlConditionClient
+	locations                 locationClient
+	insights                  insightsClient
+	defaultTags               map[string]string
+
+	defaultLocations []string
+	defaultFrequency int
+	defaultStatus    string
+
+	namePolicy    *namePolicy
+	adoptExisting bool
+
+	// claimedMonitors holds the IDs of the monitors resources have
+	// created or adopted in this run, so that no monitor is adopted by
+	// a second resource. It only lasts one run: monitors already in
+	// state aren't in it.
+	claimedMonitors   map[string]bool
+	claimedMonitorsMu sync.Mutex
+}
+
+// claimMonitor records that a resource manages a monitor. It returns
+// false if another resource already created or adopted the monitor in
+// this run.
+func (m *providerMeta) claimMonitor(id string) bool {
+	m.claimedMonitorsMu.Lock()
+	defer m.claimedMonitorsMu.Unlock()
+
+	if m.claimedMonitors[id] {
+		return false
+	}
+	if m.claimedMonitors == nil {
+		m.claimedMonitors = map[string]bool{}
+	}
+	m.claimedMonitors[id] = true
+
+	return true
+}
+
+// monitorDefaults returns the configured provider defaults, keyed by
+// the nrs_monitor attribute they default.
+func (m *providerMeta) monitorDefaults() map[string]interface{} {
+	defaults := map[string]interface{}{}
+	if len(m.defaultLocations) > 0 {
+		locations := make([]interface{}, len(m.defaultLocations))
+		for i, location := range m.defaultLocations {
+			locations[i] = location
+		}
+		defaults["locations"] = locations
+	}
+	if m.defaultFrequency != 0 {
+		defaults["frequency"] = m.defaultFrequency
+	}
+	if m.defaultStatus != "" {
+		defaults["status"] = m.defaultStatus
+	}
+
+	return defaults
 }
 
 func getClient(rd *schema.ResourceData) (interface{}, error) {
@@ -33,13 +449,90 @@ func getClient(rd *schema.ResourceData) (interface{}, error) {
 		return nil, errors.New("invalid type for new relic api key")
 	}
 
+	syntheticsBaseURL := strings.TrimSuffix(rd.Get("synthetics_base_url").(string), "/")
+	alertsBaseURL := strings.TrimSuffix(rd.Get("alerts_base_url").(string), "/")
+	insightsBaseURL := strings.TrimSuffix(rd.Get("insights_base_url").(string), "/")
+
+	transport := http.DefaultTransport
+	bases := map[string]*url.URL{}
+	for from, to := range map[string]string{
+		newrelic.DefaultSyntheticsBaseURL: syntheticsBaseURL,
+		newrelic.DefaultAlertsBaseURL:     alertsBaseURL,
+		newrelic.DefaultInsightsBaseURL:   insightsBaseURL,
+	} {
+		if from == to {
+			continue
+		}
+		u, err := url.Parse(to)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error: invalid base URL %s", to)
+		}
+		bases[from] = u
+	}
+	if len(bases) > 0 {
+		transport = &baseURLTransport{bases: bases, next: transport}
+	}
+	if rd.Get("read_only").(bool) {
+		transport = &readOnlyTransport{next: transport}
+	}
+
+	httpClient := http.DefaultClient
+	if transport != http.DefaultTransport {
+		httpClient = &http.Client{Transport: transport}
+	}
+
 	conf := func(s *synthetics.Client) {
 		s.APIKey = apiKey
+		s.HTTPClient = httpClient
 	}
 	client, err := synthetics.NewClient(conf)
 	if err != nil {
 		return nil, errors.Wrap(err, "error: could not instantiate synthetics client")
 	}
 
-	return client, nil
+	newrelicClient, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = apiKey
+		c.HTTPClient = httpClient
+		c.SyntheticsBaseURL = syntheticsBaseURL
+		c.AlertsBaseURL = alertsBaseURL
+		c.InsightsBaseURL = insightsBaseURL
+		c.AccountID = uint(rd.Get("account_id").(int))
+		c.InsightsQueryKey = rd.Get("insights_query_key").(string)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error: could not instantiate new relic client")
+	}
+
+	defaultTags := map[string]string{}
+	for category, label := range rd.Get("default_tags").(map[string]interface{}) {
+		defaultTags[category] = label.(string)
+	}
+
+	var policy *namePolicy
+	if policies := rd.Get("name_policy").([]interface{}); len(policies) > 0 {
+		p := policies[0].(map[string]interface{})
+		pattern, err := regexp.Compile(p["pattern"].(string))
+		if err != nil {
+			return nil, errors.Wrap(err, "error: invalid name_policy pattern")
+		}
+		policy = &namePolicy{pattern: pattern, prefix: p["prefix"].(string)}
+	}
+
+	return &providerMeta{
+		synthetics:                client,
+		monitorLabels:             newrelicClient,
+		alertPolicies:             newrelicClient,
+		locationFailureConditions: newrelicClient,
+		nrqlConditions:            newrelicClient,
+		locations:                 newrelicClient,
+		insights:                  newrelicClient,
+		defaultTags:               defaultTags,
+
+		defaultLocations: util.StrSlice(rd.Get("default_locations").(*schema.Set).List()),
+		defaultFrequency: rd.Get("default_frequency").(int),
+		defaultStatus:    rd.Get("default_status").(string),
+
+		namePolicy:    policy,
+		adoptExisting: rd.Get("adopt_existing").(bool),
+	}, nil
 }
@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestProviderDiffMergesDefaultTags(t *testing.T) {
+	p := Provider().(*nrsProvider)
+	p.SetMeta(&providerMeta{
+		defaultTags: map[string]string{"managed_by": "terraform", "team": "platform"},
+	})
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"name":      "monitor",
+		"type":      "SIMPLE",
+		"frequency": 5,
+		"uri":       "https://example.com",
+		"locations": []interface{}{"AWS_US_WEST_1"},
+		"status":    "ENABLED",
+		"labels":    map[string]interface{}{"team": "web"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := p.Diff(&terraform.InstanceInfo{Type: "nrs_monitor"}, nil, terraform.NewResourceConfig(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range map[string]string{
+		"tags_all.%":          "2",
+		"tags_all.managed_by": "terraform",
+		"tags_all.team":       "web",
+		"labels.team":         "web",
+	} {
+		attr, ok := diff.Attributes[k]
+		if !ok {
+			t.Errorf("%s: not in diff", k)
+			continue
+		}
+		if attr.New != want {
+			t.Errorf("%s: got %q, want %q", k, attr.New, want)
+		}
+	}
+}
+
+func TestResourceLabels(t *testing.T) {
+	defaultTags := map[string]string{"managed_by": "terraform", "team": "platform"}
+
+	for _, tc := range []struct {
+		name     string
+		previous map[string]interface{}
+		labels   map[string]interface{}
+		want     map[string]interface{}
+	}{
+		{
+			name:     "default tags left out",
+			previous: map[string]interface{}{"env": "prod"},
+			labels:   map[string]interface{}{"env": "prod", "managed_by": "terraform", "team": "platform"},
+			want:     map[string]interface{}{"env": "prod"},
+		},
+		{
+			name:     "overridden default tag kept",
+			previous: map[string]interface{}{},
+			labels:   map[string]interface{}{"managed_by": "terraform", "team": "web"},
+			want:     map[string]interface{}{"team": "web"},
+		},
+		{
+			name:     "label equal to default tag kept",
+			previous: map[string]interface{}{"team": "platform"},
+			labels:   map[string]interface{}{"managed_by": "terraform", "team": "platform"},
+			want:     map[string]interface{}{"team": "platform"},
+		},
+	} {
+		got := resourceLabels(defaultTags, tc.previous, tc.labels)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProviderDiffFillsMonitorDefaults(t *testing.T) {
+	p := Provider().(*nrsProvider)
+	p.SetMeta(&providerMeta{
+		defaultLocations: []string{"AWS_US_WEST_1"},
+		defaultFrequency: 15,
+		defaultStatus:    "ENABLED",
+	})
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"name":   "monitor",
+		"type":   "SIMPLE",
+		"uri":    "https://example.com",
+		"status": "MUTED",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := terraform.NewResourceConfig(raw)
+
+	if _, errs := p.ValidateResource("nrs_monitor", c); len(errs) > 0 {
+		t.Fatalf("unexpected validation errors: %v", errs)
+	}
+
+	diff, err := p.Diff(&terraform.InstanceInfo{Type: "nrs_monitor"}, nil, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range map[string]string{
+		"frequency":   "15",
+		"status":      "MUTED",
+		"locations.#": "1",
+	} {
+		attr, ok := diff.Attributes[k]
+		if !ok {
+			t.Errorf("%s: not in diff", k)
+			continue
+		}
+		if attr.New != want {
+			t.Errorf("%s: got %q, want %q", k, attr.New, want)
+		}
+	}
+
+	p.SetMeta(&providerMeta{})
+	if _, errs := p.ValidateResource("nrs_monitor", c); len(errs) != 2 {
+		t.Errorf("got %v, want errors for locations and frequency", errs)
+	}
+}
+
+func TestProviderNamePolicy(t *testing.T) {
+	p := Provider().(*nrsProvider)
+	p.SetMeta(&providerMeta{
+		namePolicy: &namePolicy{
+			pattern: regexp.MustCompile(`^(prod|staging)-[a-z]+-[a-z]+$`),
+			prefix:  "prod-",
+		},
+	})
+
+	for _, tc := range []struct {
+		name  string
+		valid bool
+		want  string
+	}{
+		{name: "web-homepage", valid: true, want: "prod-web-homepage"},
+		{name: "prod-web-homepage", valid: true, want: "prod-web-homepage"},
+		{name: "Web Homepage", valid: false},
+	} {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"name":       tc.name,
+			"policy_id":  1,
+			"monitor_id": "monitor",
+			"enabled":    true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := terraform.NewResourceConfig(raw)
+
+		_, errs := p.ValidateResource("nrs_alert_condition", c)
+		if valid := len(errs) == 0; valid != tc.valid {
+			t.Errorf("%s: got errors %v, want valid %v", tc.name, errs, tc.valid)
+		}
+		if !tc.valid {
+			continue
+		}
+
+		diff, err := p.Diff(&terraform.InstanceInfo{Type: "nrs_alert_condition"}, nil, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := diff.Attributes["name"].New; got != tc.want {
+			t.Errorf("%s: got name %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProviderDiffUnlabelledMonitor(t *testing.T) {
+	_, meta := newFakeMeta()
+	p := Provider().(*nrsProvider)
+	p.SetMeta(meta)
+
+	// Terraform saves the state without the empty tags_all.
+	state := applyResource(t, NRSMonitorResource(), nil, simpleMonitorConfig(), meta)
+	delete(state.Attributes, "tags_all.%")
+
+	raw, err := config.NewRawConfig(simpleMonitorConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := p.Diff(&terraform.InstanceInfo{Type: "nrs_monitor"}, state, terraform.NewResourceConfig(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		t.Errorf("expected no diff for an unchanged monitor without labels, got %#v", diff.Attributes)
+	}
+}
+
+func TestProviderDiffWithoutDeletionProtection(t *testing.T) {
+	_, meta := newFakeMeta()
+	p := Provider().(*nrsProvider)
+	p.SetMeta(meta)
+
+	conditionConfig := map[string]interface{}{
+		"name":       "condition",
+		"monitor_id": "monitor",
+		"policy_id":  1,
+		"enabled":    true,
+	}
+	tests := []struct {
+		resourceType string
+		resource     *schema.Resource
+		raw          map[string]interface{}
+	}{
+		{"nrs_monitor", NRSMonitorResource(), simpleMonitorConfig()},
+		{"nrs_alert_condition", NRSAlertConditionResource(), conditionConfig},
+	}
+
+	for _, test := range tests {
+		// State saved before deletion_protection was added.
+		state := applyResource(t, test.resource, nil, test.raw, meta)
+		delete(state.Attributes, "deletion_protection")
+
+		raw, err := config.NewRawConfig(test.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := p.Diff(&terraform.InstanceInfo{Type: test.resourceType}, state, terraform.NewResourceConfig(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != nil {
+			t.Errorf("%s: expected no diff, got %#v", test.resourceType, diff.Attributes)
+		}
+
+		// Turning protection on is still planned.
+		test.raw["deletion_protection"] = true
+		raw, err = config.NewRawConfig(test.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err = p.Diff(&terraform.InstanceInfo{Type: test.resourceType}, state, terraform.NewResourceConfig(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil || diff.Attributes["deletion_protection"] == nil || diff.Attributes["deletion_protection"].New != "true" {
+			t.Errorf("%s: expected deletion_protection to be turned on, got %#v", test.resourceType, diff)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// readOnlyTransport only lets requests through that can't change
+// anything in New Relic. Both API clients send every request through
+// it when the provider is read_only, so no resource function can
+// create, update or delete anything, while reads, refreshes, imports
+// and data sources keep working.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends GET and HEAD requests and refuses everything else.
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, errors.Errorf("error: the provider is read_only, refusing to %s %s", req.Method, req.URL.Path)
+	}
+
+	return t.next.RoundTrip(req)
+}
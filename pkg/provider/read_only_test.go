@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &readOnlyTransport{next: http.DefaultTransport}}
+
+	resp, err := client.Get(server.URL + "/v3/monitors")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, err := http.NewRequest(method, server.URL+"/v3/monitors/id", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		_, err = client.Do(req)
+		if err == nil || !strings.Contains(err.Error(), "read_only") {
+			t.Errorf("%s: expected a read_only error, got %v", method, err)
+		}
+	}
+
+	if len(requests) != 1 || requests[0] != http.MethodGet {
+		t.Errorf("expected only the GET request to reach the server, got %v", requests)
+	}
+}
+
+func TestReadOnlyMeta(t *testing.T) {
+	defer os.Setenv("NEWRELIC_API_KEY", os.Getenv("NEWRELIC_API_KEY"))
+	os.Setenv("NEWRELIC_API_KEY", "test")
+
+	meta, err := ReadOnlyMeta()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client := meta.(*providerMeta).monitorLabels.(*newrelic.Client)
+	if client.APIKey != "test" {
+		t.Errorf("expected the API key from the environment, got %q", client.APIKey)
+	}
+	if _, ok := client.HTTPClient.Transport.(*readOnlyTransport); !ok {
+		t.Errorf("expected a read-only transport, got %T", client.HTTPClient.Transport)
+	}
+}
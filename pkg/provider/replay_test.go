@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/recorder"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// replayAPIKey returns the API key to send: NEWRELIC_API_KEY when
+// recording, and the redacted key the cassettes have when replaying.
+func replayAPIKey() string {
+	if recorder.ModeFromEnv() == recorder.Record {
+		return os.Getenv("NEWRELIC_API_KEY")
+	}
+	return recorder.Redacted
+}
+
+// newReplayMeta returns provider clients that record to or replay the
+// cassette of a test in testdata/cassettes, and a function that stops
+// the recorder. Cassettes are only recorded against New Relic, so
+// recording fails without NEWRELIC_API_KEY. Replaying is skipped until
+// a cassette has been recorded.
+func newReplayMeta(t *testing.T) (*providerMeta, *recorder.Recorder, func()) {
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+	mode := recorder.ModeFromEnv()
+
+	if mode == recorder.Record {
+		if os.Getenv("NEWRELIC_API_KEY") == "" {
+			t.Fatalf("%s=1 records cassettes against New Relic; set NEWRELIC_API_KEY to an account's admin API key", recorder.RecordEnv)
+		}
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Skipf("no cassette at %s; record one against New Relic with NEWRELIC_API_KEY set and %s=1", path, recorder.RecordEnv)
+	}
+
+	apiKey := replayAPIKey()
+	rec := recorder.New(t, path, mode, nil, apiKey)
+
+	client, err := synthetics.NewClient(func(s *synthetics.Client) {
+		s.APIKey = apiKey
+		s.HTTPClient = rec.Client()
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	newrelicClient, err := newrelic.NewClient(func(c *newrelic.Client) {
+		c.APIKey = apiKey
+		c.HTTPClient = rec.Client()
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return &providerMeta{
+		synthetics:                client,
+		monitorLabels:             newrelicClient,
+		alertPolicies:             newrelicClient,
+		locationFailureConditions: newrelicClient,
+		nrqlConditions:            newrelicClient,
+		locations:                 newrelicClient,
+		insights:                  newrelicClient,
+	}, rec, rec.Stop
+}
+
+func TestReplayMonitor(t *testing.T) {
+	meta, _, stop := newReplayMeta(t)
+	defer stop()
+
+	resource := NRSMonitorResource()
+	raw := map[string]interface{}{
+		"name":      "tf-replay-monitor",
+		"type":      "SCRIPT_API",
+		"frequency": 5,
+		"locations": []interface{}{"AWS_US_WEST_1"},
+		"status":    "ENABLED",
+		"script":    "console.log('check')",
+		"labels":    map[string]interface{}{"Team": "web"},
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	raw["frequency"] = 15
+	raw["script"] = "console.log('check again')"
+	raw["labels"] = map[string]interface{}{"Team": "api"}
+	state = applyResource(t, resource, state, raw, meta)
+
+	if _, err := resource.Apply(state, &terraform.InstanceDiff{Destroy: true}, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// replayMonitor creates a SIMPLE monitor with a plain request to the
+// Synthetics API and returns a function that deletes it, so that tests
+// of the New Relic client don't depend on the Synthetics client.
+func replayMonitor(t *testing.T, rec *recorder.Recorder, name string) (string, func()) {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":      name,
+		"type":      "SIMPLE",
+		"frequency": 5,
+		"uri":       "https://example.com",
+		"locations": []string{"AWS_US_WEST_1"},
+		"status":    "ENABLED",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	send := func(method, u string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, u, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		req.Header.Set("X-Api-Key", replayAPIKey())
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := rec.Client().Do(req)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := send(http.MethodPost, newrelic.DefaultSyntheticsBaseURL+"/v3/monitors", body)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("could not create monitor: status %d", resp.StatusCode)
+	}
+	id := path.Base(resp.Header.Get("Location"))
+
+	return id, func() {
+		if resp := send(http.MethodDelete, newrelic.DefaultSyntheticsBaseURL+"/v3/monitors/"+id, nil); resp.StatusCode != http.StatusNoContent {
+			t.Errorf("could not delete monitor %s: status %d", id, resp.StatusCode)
+		}
+	}
+}
+
+func TestReplayMonitorLabels(t *testing.T) {
+	meta, rec, stop := newReplayMeta(t)
+	defer stop()
+
+	monitorID, deleteMonitor := replayMonitor(t, rec, "tf-replay-monitor-labels")
+	defer deleteMonitor()
+
+	steps := []map[string]interface{}{
+		{"Team": "web", "Env": "prod", "Tier": "1"},
+		{"Team": "api", "Env": "prod", "Owner": "checkout"},
+		{},
+	}
+	var labels map[string]interface{}
+	for _, expected := range steps {
+		if err := updateMonitorLabels(meta.monitorLabels, monitorID, labels, expected); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		var err error
+		labels, err = readMonitorLabels(meta.monitorLabels, monitorID)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(labels, expected) {
+			t.Errorf("expected labels %v, got %v", expected, labels)
+		}
+	}
+}
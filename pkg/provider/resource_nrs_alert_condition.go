@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/pkg/errors"
 )
@@ -42,11 +43,16 @@ func NRSAlertConditionResource() *schema.Resource {
 				Required:    true,
 				Description: "Whether the alert condition is enabled",
 			},
+			"deletion_protection": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to delete the alert condition; must be set to false in a separate apply before deleting",
+			},
 			"policy_id": &schema.Schema{
 				Type:        schema.TypeInt,
 				Required:    true,
 				Description: "The ID of the policy to attach the alert condition to",
-				ForceNew:    true,
 			},
 		},
 		Create: NRSAlertConditionCreate,
@@ -63,15 +69,13 @@ func NRSAlertConditionResource() *schema.Resource {
 // NRSAlertConditionCreate creates a Synthetics alert condition using
 // Terraform configuration.
 func NRSAlertConditionCreate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
 
 	args := &synthetics.CreateAlertConditionArgs{
-		Name:      resourceData.Get("name").(string),
-		MonitorID: resourceData.Get("monitor_id").(string),
-		Enabled:   resourceData.Get("enabled").(bool),
-	}
-	if data, ok := resourceData.GetOk("runbook_url"); ok {
-		args.RunbookURL = data.(string)
+		Name:       resourceData.Get("name").(string),
+		MonitorID:  resourceData.Get("monitor_id").(string),
+		Enabled:    resourceData.Get("enabled").(bool),
+		RunbookURL: resourceData.Get("runbook_url").(string),
 	}
 
 	alertCondition, err := client.CreateAlertCondition(uint(resourceData.Get("policy_id").(int)), args)
@@ -81,32 +85,117 @@ func NRSAlertConditionCreate(resourceData *schema.ResourceData, meta interface{}
 
 	resourceData.SetId(fmt.Sprintf("%d", alertCondition.ID))
 
-	return nil
+	return NRSAlertConditionRead(resourceData, meta)
 }
+
+// alertConditionID returns the ID of an alert condition from Terraform
+// state.
+func alertConditionID(resourceData *schema.ResourceData) (uint, error) {
+	id, err := strconv.ParseUint(resourceData.Id(), 10, 0)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error: invalid alert condition ID %q", resourceData.Id())
+	}
+
+	return uint(id), nil
+}
+
 // NRSAlertConditionImportState imports given condition to Terraform state
-// using policy_id and condition_id from New Relic alerts API
+// using policy_id and either condition_id, monitor/<monitor_id> or
+// name/<condition name> from New Relic alerts API
 func NRSAlertConditionImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	s := strings.Split(d.Id(), ":")
+	s := strings.SplitN(d.Id(), ":", 2)
 	if len(s) != 2 {
 		/*
 		   In New Relic alert condition , we need both policy_id  and condition_id to get given user data.
 		*/
-		return nil, fmt.Errorf("Import resource ID should consist of policy_id:condition_id")
+		return nil, fmt.Errorf("Import resource ID should consist of policy_id:condition_id, policy_id:monitor/<monitor_id> or policy_id:name/<condition name>")
+	}
+
+	policyID, err := strconv.Atoi(s[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: invalid policy ID %q", s[0])
 	}
 
-	val, _ := strconv.Atoi(s[0])
-	d.SetId(s[1])
-	d.Set("policy_id", val)
+	var conditionID uint
+	switch {
+	case strings.HasPrefix(s[1], "monitor/"):
+		monitorID := strings.TrimPrefix(s[1], "monitor/")
+		condition, err := findAlertCondition(meta, uint(policyID), fmt.Sprintf("monitor %q", monitorID), func(c *newrelic.SyntheticsCondition) bool {
+			return c.MonitorID == monitorID
+		})
+		if err != nil {
+			return nil, err
+		}
+		conditionID = condition.ID
+	case strings.HasPrefix(s[1], "name/"):
+		name := strings.TrimPrefix(s[1], "name/")
+		condition, err := findAlertCondition(meta, uint(policyID), fmt.Sprintf("name %q", name), func(c *newrelic.SyntheticsCondition) bool {
+			return c.Name == name
+		})
+		if err != nil {
+			return nil, err
+		}
+		conditionID = condition.ID
+	default:
+		id, err := strconv.ParseUint(s[1], 10, 0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error: invalid alert condition ID %q", s[1])
+		}
+		conditionID = uint(id)
+	}
+
+	d.SetId(fmt.Sprintf("%d", conditionID))
+	if err := d.Set("policy_id", policyID); err != nil {
+		return nil, err
+	}
+	// Set the default, which isn't applied to imported resources.
+	if err := d.Set("deletion_protection", false); err != nil {
+		return nil, err
+	}
 
 	return []*schema.ResourceData{d}, nil
 }
 
+// findAlertCondition returns the only alert condition in a policy
+// that matches. description describes the match in errors.
+func findAlertCondition(meta interface{}, policyID uint, description string, match func(*newrelic.SyntheticsCondition) bool) (*newrelic.SyntheticsCondition, error) {
+	conditions, err := meta.(*providerMeta).alertPolicies.GetSyntheticsConditions(policyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: could not list alert conditions in policy %d", policyID)
+	}
+
+	var matches []*newrelic.SyntheticsCondition
+	for _, condition := range conditions {
+		if match(condition) {
+			matches = append(matches, condition)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("error: no alert condition in policy %d has %s", policyID, description)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, condition := range matches {
+			ids[i] = fmt.Sprintf("%d", condition.ID)
+		}
+		return nil, fmt.Errorf("error: %d alert conditions in policy %d have %s (%s)", len(matches), policyID, description, strings.Join(ids, ", "))
+	}
+}
+
 // NRSAlertConditionExists checks whether an alert condition exists
 // using Terraform configuration.
 func NRSAlertConditionExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
 
-	_, err := client.GetAlertCondition(uint(resourceData.Get("policy_id").(int)), uint(resourceData.Get("id").(int)))
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.GetAlertCondition(uint(resourceData.Get("policy_id").(int)), id)
 	if err == synthetics.ErrAlertConditionNotFound {
 		return false, nil
 	}
@@ -120,9 +209,19 @@ func NRSAlertConditionExists(resourceData *schema.ResourceData, meta interface{}
 // NRSAlertConditionDelete deletes a Synthetics alert condition using
 // Terraform configuration.
 func NRSAlertConditionDelete(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
+
+	if resourceData.Get("deletion_protection").(bool) {
+		return errors.Errorf("error: alert condition %s has deletion_protection set; set it to false and apply before deleting the alert condition", resourceData.Id())
+	}
 
-	if err := client.DeleteAlertCondition(uint(resourceData.Get("id").(int))); err != nil {
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteAlertCondition(id)
+	if err != nil && err != synthetics.ErrAlertConditionNotFound {
 		return errors.Wrap(err, "error: could not delete alert condition")
 	}
 
@@ -132,9 +231,18 @@ func NRSAlertConditionDelete(resourceData *schema.ResourceData, meta interface{}
 // NRSAlertConditionRead refreshes alert condition information using
 // Terraform configuration.
 func NRSAlertConditionRead(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
 
-	ac, err := client.GetAlertCondition(uint(resourceData.Get("policy_id").(int)), uint(resourceData.Get("id").(int)))
+	// New Relic only lists conditions by policy, so policy_id is taken
+	// from state rather than read back. A condition moved to another
+	// policy outside Terraform isn't found there, and Exists drops it
+	// from state.
+	ac, err := client.GetAlertCondition(uint(resourceData.Get("policy_id").(int)), id)
 	if err != nil {
 		return errors.Wrapf(err, "error: could not find alert condition")
 	}
@@ -156,22 +264,115 @@ func NRSAlertConditionRead(resourceData *schema.ResourceData, meta interface{})
 }
 
 // NRSAlertConditionUpdate updates a Synthetics alert condition using
-// Terraform configuration.
+// Terraform configuration. Every update sends the full desired state,
+// since New Relic replaces the condition with what it receives.
 func NRSAlertConditionUpdate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
+
+	if resourceData.HasChange("policy_id") {
+		if err := moveAlertCondition(resourceData, meta.(*providerMeta)); err != nil {
+			return err
+		}
+		return NRSAlertConditionRead(resourceData, meta)
+	}
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
 
 	args := &synthetics.UpdateAlertConditionArgs{
-		Name:      resourceData.Get("name").(string),
-		MonitorID: resourceData.Get("monitor_id").(string),
-		Enabled:   resourceData.Get("enabled").(bool),
+		Name:       resourceData.Get("name").(string),
+		MonitorID:  resourceData.Get("monitor_id").(string),
+		Enabled:    resourceData.Get("enabled").(bool),
+		RunbookURL: resourceData.Get("runbook_url").(string),
 	}
-	if resourceData.HasChange("runbook_url") {
-		args.RunbookURL = resourceData.Get("runbook_url").(string)
+
+	if _, err := client.UpdateAlertCondition(id, args); err != nil {
+		return errors.Wrapf(err, "error: could not update alert condition")
 	}
 
-	_, err := client.UpdateAlertCondition(uint(resourceData.Get("policy_id").(int)), args)
+	return NRSAlertConditionRead(resourceData, meta)
+}
+
+// moveAlertCondition moves an alert condition to the policy in
+// Terraform configuration. It first asks New Relic to update the
+// condition with the new policy ID, and checks that the condition
+// shows up in the new policy. Only when New Relic refuses the update,
+// or accepts it without moving the condition, is the condition
+// recreated in the new policy.
+func moveAlertCondition(resourceData *schema.ResourceData, meta *providerMeta) error {
+	newPolicyID := uint(resourceData.Get("policy_id").(int))
+
+	id, err := alertConditionID(resourceData)
 	if err != nil {
-		return errors.Wrapf(err, "error: could not update alert condition")
+		return err
+	}
+
+	condition := &newrelic.SyntheticsCondition{
+		Name:       resourceData.Get("name").(string),
+		MonitorID:  resourceData.Get("monitor_id").(string),
+		Enabled:    resourceData.Get("enabled").(bool),
+		RunbookURL: resourceData.Get("runbook_url").(string),
+		PolicyID:   newPolicyID,
+	}
+	_, err = meta.alertPolicies.UpdateSyntheticsCondition(id, condition)
+	if err != nil && !newrelic.IsRefused(err) {
+		return errors.Wrapf(err, "error: could not move alert condition %d to policy %d", id, newPolicyID)
+	}
+	if err == nil {
+		_, err := meta.synthetics.GetAlertCondition(newPolicyID, id)
+		if err == nil {
+			return nil
+		}
+		if err != synthetics.ErrAlertConditionNotFound {
+			return errors.Wrapf(err, "error: could not check whether alert condition %d moved to policy %d", id, newPolicyID)
+		}
+	}
+
+	// Recreating the condition deletes the old one, which protection
+	// must be turned off for first, the same as for a delete.
+	if protected, _ := resourceData.GetChange("deletion_protection"); protected.(bool) {
+		return errors.Errorf("error: alert condition %d can't be moved to policy %d in place, and recreating it there would delete it, but it has deletion_protection set; set it to false and apply before moving the alert condition", id, newPolicyID)
+	}
+
+	return recreateAlertCondition(resourceData, meta.synthetics)
+}
+
+// recreateAlertCondition moves an alert condition New Relic won't move
+// in place. The condition is created in the new policy before it is
+// deleted from the old one, so the monitor is covered by at least one
+// condition throughout the move.
+func recreateAlertCondition(resourceData *schema.ResourceData, client alertConditionClient) error {
+	oldPolicyID, newPolicyID := resourceData.GetChange("policy_id")
+
+	oldID, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	args := &synthetics.CreateAlertConditionArgs{
+		Name:       resourceData.Get("name").(string),
+		MonitorID:  resourceData.Get("monitor_id").(string),
+		Enabled:    resourceData.Get("enabled").(bool),
+		RunbookURL: resourceData.Get("runbook_url").(string),
+	}
+	alertCondition, err := client.CreateAlertCondition(uint(newPolicyID.(int)), args)
+	if err != nil {
+		return errors.Wrapf(err, "error: could not create alert condition in policy %d", newPolicyID.(int))
+	}
+
+	// From here on the state tracks the new condition, even if the
+	// old one can't be deleted.
+	resourceData.SetId(fmt.Sprintf("%d", alertCondition.ID))
+
+	err = client.DeleteAlertCondition(oldID)
+	if err != nil && err != synthetics.ErrAlertConditionNotFound {
+		return errors.Wrapf(
+			err,
+			"error: moved alert condition to policy %d as %d, but could not delete alert condition %d from policy %d; delete it manually",
+			newPolicyID.(int), alertCondition.ID, oldID, oldPolicyID.(int),
+		)
 	}
 
 	return nil
@@ -0,0 +1,388 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/fake"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// applyResource plans and applies raw configuration over state, then
+// refreshes the result, the way Terraform does across two runs.
+func applyResource(t *testing.T, resource *schema.Resource, state *terraform.InstanceState, raw map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	diff, err := resource.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = resource.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = resource.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// A refreshed state must not produce a new plan.
+	diff, err = resource.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("expected an empty plan after refresh, got %#v", diff.Attributes)
+	}
+
+	return state
+}
+
+func checkAttributes(t *testing.T, state *terraform.InstanceState, expected map[string]string) {
+	for key, value := range expected {
+		if state.Attributes[key] != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, state.Attributes[key])
+		}
+	}
+}
+
+func TestAlertConditionRoundTrip(t *testing.T) {
+	_, meta := newFakeMeta()
+	resource := NRSAlertConditionResource()
+
+	state := applyResource(t, resource, nil, map[string]interface{}{
+		"name":        "condition",
+		"monitor_id":  "monitor",
+		"runbook_url": "https://example.com/runbook",
+		"enabled":     true,
+		"policy_id":   1,
+	}, meta)
+	checkAttributes(t, state, map[string]string{
+		"id":          "101",
+		"name":        "condition",
+		"monitor_id":  "monitor",
+		"runbook_url": "https://example.com/runbook",
+		"enabled":     "true",
+		"policy_id":   "1",
+	})
+
+	// Changing one attribute must keep every other one.
+	state = applyResource(t, resource, state, map[string]interface{}{
+		"name":        "renamed",
+		"monitor_id":  "monitor",
+		"runbook_url": "https://example.com/runbook",
+		"enabled":     true,
+		"policy_id":   1,
+	}, meta)
+	checkAttributes(t, state, map[string]string{
+		"id":          "101",
+		"name":        "renamed",
+		"runbook_url": "https://example.com/runbook",
+		"enabled":     "true",
+	})
+
+	// Removing the runbook URL must clear it.
+	state = applyResource(t, resource, state, map[string]interface{}{
+		"name":       "renamed",
+		"monitor_id": "monitor",
+		"enabled":    false,
+		"policy_id":  1,
+	}, meta)
+	checkAttributes(t, state, map[string]string{
+		"id":          "101",
+		"name":        "renamed",
+		"runbook_url": "",
+		"enabled":     "false",
+	})
+}
+
+func TestAlertConditionUpdateTargetsCondition(t *testing.T) {
+	client := fake.NewClient()
+	meta := &providerMeta{synthetics: client}
+	resource := NRSAlertConditionResource()
+
+	raw := map[string]interface{}{
+		"name":       "first",
+		"monitor_id": "monitor",
+		"enabled":    true,
+		"policy_id":  1,
+	}
+	first := applyResource(t, resource, nil, raw, meta)
+	raw["name"] = "second"
+	applyResource(t, resource, nil, raw, meta)
+
+	raw["name"] = "first-renamed"
+	applyResource(t, resource, first, raw, meta)
+
+	if name := client.AlertConditions[101].Name; name != "first-renamed" {
+		t.Errorf("expected the first condition to be renamed, got %q", name)
+	}
+	if name := client.AlertConditions[102].Name; name != "second" {
+		t.Errorf("expected the second condition to be untouched, got %q", name)
+	}
+}
+
+func TestAlertConditionImportState(t *testing.T) {
+	client, meta := newFakeMeta()
+	for _, condition := range []*synthetics.AlertCondition{
+		{ID: 10, Name: "first", MonitorID: "a", Enabled: true},
+		{ID: 11, Name: "dup", MonitorID: "b", Enabled: true},
+		{ID: 12, Name: "dup", MonitorID: "b", Enabled: true},
+	} {
+		client.AlertConditions[condition.ID] = condition
+		client.AlertConditionPolicies[condition.ID] = 1
+	}
+
+	tests := []struct {
+		importID string
+		id       string
+		fails    bool
+	}{
+		{importID: "1:10", id: "10"},
+		{importID: "1:monitor/a", id: "10"},
+		{importID: "1:name/first", id: "10"},
+		{importID: "1:name/dup", fails: true},
+		{importID: "1:monitor/b", fails: true},
+		{importID: "1:name/missing", fails: true},
+		{importID: "10", fails: true},
+	}
+
+	for _, test := range tests {
+		resourceData := NRSAlertConditionResource().Data(nil)
+		resourceData.SetId(test.importID)
+
+		imported, err := NRSAlertConditionImportState(resourceData, meta)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.importID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: err: %s", test.importID, err)
+			continue
+		}
+		if id := imported[0].Id(); id != test.id {
+			t.Errorf("%s: expected ID %s, got %s", test.importID, test.id, id)
+		}
+		if policyID := imported[0].Get("policy_id").(int); policyID != 1 {
+			t.Errorf("%s: expected policy ID 1, got %d", test.importID, policyID)
+		}
+		if protection, ok := imported[0].State().Attributes["deletion_protection"]; !ok || protection != "false" {
+			t.Errorf("%s: expected deletion_protection to be false, got %q", test.importID, protection)
+		}
+	}
+}
+
+func TestAlertConditionDeletionProtection(t *testing.T) {
+	client := fake.NewClient()
+	meta := &providerMeta{synthetics: client}
+	resource := NRSAlertConditionResource()
+
+	raw := map[string]interface{}{
+		"name":                "condition",
+		"monitor_id":          "monitor",
+		"policy_id":           1,
+		"enabled":             true,
+		"deletion_protection": true,
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	destroy := &terraform.InstanceDiff{Destroy: true}
+	if _, err := resource.Apply(state, destroy, meta); err == nil {
+		t.Fatal("expected deleting a protected alert condition to fail")
+	}
+	if len(client.AlertConditions) != 1 {
+		t.Fatalf("expected the protected alert condition to remain, got %d conditions", len(client.AlertConditions))
+	}
+
+	raw["deletion_protection"] = false
+	state = applyResource(t, resource, state, raw, meta)
+	if _, err := resource.Apply(state, destroy, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(client.AlertConditions) != 0 {
+		t.Fatalf("expected the alert condition to be deleted, got %d conditions", len(client.AlertConditions))
+	}
+}
+
+func TestAlertConditionInjectedErrors(t *testing.T) {
+	client := fake.NewClient()
+	meta := &providerMeta{synthetics: client}
+	resource := NRSAlertConditionResource()
+
+	raw := map[string]interface{}{
+		"name":       "condition",
+		"monitor_id": "monitor",
+		"policy_id":  1,
+		"enabled":    true,
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	raw["name"] = "renamed"
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := resource.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client.Errors["UpdateAlertCondition"] = errors.New("injected")
+	if _, err := resource.Apply(state, diff, meta); err == nil {
+		t.Fatal("expected a failing UpdateAlertCondition to fail the apply")
+	}
+	for _, condition := range client.AlertConditions {
+		if condition.Name != "condition" {
+			t.Errorf("expected the alert condition to be unchanged, got %q", condition.Name)
+		}
+	}
+
+	client.Errors["GetAlertCondition"] = errors.New("injected")
+	if _, err := resource.Refresh(state, meta); err == nil {
+		t.Fatal("expected a failing GetAlertCondition to fail the refresh")
+	}
+}
+
+func TestAlertConditionMove(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(client *fake.Client)
+		id        string
+		policies  map[uint]uint
+		fails     bool
+		protected bool
+	}{
+		{
+			name:     "in place",
+			setup:    func(client *fake.Client) {},
+			id:       "101",
+			policies: map[uint]uint{101: 2},
+		},
+		{
+			name: "refused",
+			setup: func(client *fake.Client) {
+				client.Errors["UpdateSyntheticsCondition"] = &newrelic.APIError{StatusCode: http.StatusUnprocessableEntity}
+			},
+			id:       "102",
+			policies: map[uint]uint{102: 2},
+		},
+		{
+			name: "ignored",
+			setup: func(client *fake.Client) {
+				client.IgnoreConditionMoves = true
+			},
+			id:       "102",
+			policies: map[uint]uint{102: 2},
+		},
+		{
+			name: "failed",
+			setup: func(client *fake.Client) {
+				client.Errors["UpdateSyntheticsCondition"] = &newrelic.APIError{StatusCode: http.StatusInternalServerError}
+			},
+			id:       "101",
+			policies: map[uint]uint{101: 1},
+			fails:    true,
+		},
+		{
+			name: "refused and old condition not deleted",
+			setup: func(client *fake.Client) {
+				client.Errors["UpdateSyntheticsCondition"] = &newrelic.APIError{StatusCode: http.StatusBadRequest}
+				client.Errors["DeleteAlertCondition"] = errors.New("injected")
+			},
+			id:       "102",
+			policies: map[uint]uint{101: 1, 102: 2},
+			fails:    true,
+		},
+		{
+			name: "refused and protected",
+			setup: func(client *fake.Client) {
+				client.Errors["UpdateSyntheticsCondition"] = &newrelic.APIError{StatusCode: http.StatusUnprocessableEntity}
+			},
+			id:        "101",
+			policies:  map[uint]uint{101: 1},
+			fails:     true,
+			protected: true,
+		},
+		{
+			name:      "in place and protected",
+			setup:     func(client *fake.Client) {},
+			id:        "101",
+			policies:  map[uint]uint{101: 2},
+			protected: true,
+		},
+	}
+
+	for _, test := range tests {
+		client, meta := newFakeMeta()
+		resource := NRSAlertConditionResource()
+
+		raw := map[string]interface{}{
+			"name":       "condition",
+			"monitor_id": "monitor",
+			"policy_id":  1,
+			"enabled":    true,
+		}
+		if test.protected {
+			raw["deletion_protection"] = true
+		}
+		state := applyResource(t, resource, nil, raw, meta)
+		test.setup(client)
+
+		raw["policy_id"] = 2
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		diff, err := resource.Diff(state, terraform.NewResourceConfig(c))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		state, err = resource.Apply(state, diff, meta)
+		if test.fails != (err != nil) {
+			t.Fatalf("%s: expected failure %t, got %v", test.name, test.fails, err)
+		}
+
+		// Once a condition exists in the new policy, the state tracks
+		// it, even when the old one is left behind.
+		if state.ID != test.id {
+			t.Errorf("%s: expected ID %s, got %s", test.name, test.id, state.ID)
+		}
+		if !reflect.DeepEqual(client.AlertConditionPolicies, test.policies) {
+			t.Errorf("%s: expected conditions in policies %v, got %v", test.name, test.policies, client.AlertConditionPolicies)
+		}
+	}
+}
+
+func TestAlertConditionMovedOutOfBand(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSAlertConditionResource()
+
+	state := applyResource(t, resource, nil, map[string]interface{}{
+		"name":       "condition",
+		"monitor_id": "monitor",
+		"policy_id":  1,
+		"enabled":    true,
+	}, meta)
+
+	// policy_id comes from state, so a condition moved to another
+	// policy isn't found and leaves the state.
+	client.AlertConditionPolicies[101] = 2
+	refreshed, err := resource.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if refreshed != nil {
+		t.Errorf("expected the moved alert condition to leave the state, got %#v", refreshed.Attributes)
+	}
+}
@@ -2,9 +2,14 @@ package provider
 
 import (
 	"crypto/sha256"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
 
 	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
 	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/pkg/errors"
@@ -24,9 +29,11 @@ func NRSMonitorResource() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			// locations, frequency and status are required unless the
+			// provider has a default for them; see nrsProvider.
 			"frequency": &schema.Schema{
 				Type:        schema.TypeInt,
-				Required:    true,
+				Optional:    true,
 				Description: "The monitor's checking frequency in minutes (one of 1, 5, 10, 15, 30, 60, 360, 720, or 1440)",
 			},
 			"uri": &schema.Schema{
@@ -36,14 +43,13 @@ func NRSMonitorResource() *schema.Resource {
 			},
 			"locations": &schema.Schema{
 				Type:        schema.TypeSet,
-				Required:    true,
+				Optional:    true,
 				Description: "The locations to check from",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"status": &schema.Schema{
 				Type:         schema.TypeString,
-				Required:     true,
-				InputDefault: "ENABLED",
+				Optional:     true,
 				Description:  "The monitor's status (one of ENABLED, MUTED, DISABLED)",
 				ValidateFunc: validation.StringInSlice([]string{"ENABLED", "MUTED", "DISABLED"}, false),
 			},
@@ -98,6 +104,33 @@ func NRSMonitorResource() *schema.Resource {
 					},
 				},
 			},
+			"labels": &schema.Schema{
+				Type:        schema.TypeMap,
+				Description: "The monitor's labels, as a map of category to label",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			// Set by the provider from labels and default_tags; see
+			// nrsProvider.Diff.
+			"tags_all": &schema.Schema{
+				Type:        schema.TypeMap,
+				Description: "The monitor's labels merged with the provider's default_tags",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"deletion_protection": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Refuse to delete the monitor; must be set to false in a separate apply before deleting",
+				Optional:    true,
+				Default:     false,
+			},
+			// Defaults to the provider's adopt_existing; see nrsProvider.
+			"adopt_existing": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Take over an existing monitor with the same name and type instead of creating one. Only monitors created or adopted by another resource in the same run are refused; a monitor in another resource's state from an earlier run is adopted again",
+				Optional:    true,
+			},
 			"type": &schema.Schema{
 				Type:         schema.TypeString,
 				Description:  "The type of monitor (one of SIMPLE, BROWSER, SCRIPT_API, SCRIPT_BROWSER)",
@@ -112,7 +145,7 @@ func NRSMonitorResource() *schema.Resource {
 		Read:   NRSMonitorRead,
 		Update: NRSMonitorUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: NRSMonitorImportState,
 		},
 	}
 }
@@ -124,10 +157,115 @@ func sha256StateFunc(i interface{}) string {
 	return string(hash.Sum(nil))
 }
 
+// expandScriptLocations returns the script locations in Terraform
+// configuration.
+func expandScriptLocations(resourceData *schema.ResourceData) []*synthetics.ScriptLocation {
+	var scriptLocations []*synthetics.ScriptLocation
+	for _, data := range resourceData.Get("script_locations").([]interface{}) {
+		scriptLocation := data.(map[string]interface{})
+		scriptLocations = append(
+			scriptLocations,
+			&synthetics.ScriptLocation{
+				Name: scriptLocation["name"].(string),
+				HMAC: scriptLocation["hmac"].(string),
+			},
+		)
+	}
+
+	return scriptLocations
+}
+
+// monitorsPageSize is the number of monitors requested per page when
+// listing monitors.
+const monitorsPageSize = 100
+
+// getAllMonitors returns every monitor in the account.
+func getAllMonitors(client monitorClient) ([]*synthetics.ExtendedMonitor, error) {
+	var monitors []*synthetics.ExtendedMonitor
+	for offset := uint(0); ; offset += monitorsPageSize {
+		resp, err := client.GetAllMonitors(offset, monitorsPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "error: could not list monitors")
+		}
+
+		monitors = append(monitors, resp.Monitors...)
+		if len(resp.Monitors) < monitorsPageSize || uint(len(monitors)) >= resp.Count {
+			return monitors, nil
+		}
+	}
+}
+
+// findMonitorByName returns the only monitor with the given name.
+func findMonitorByName(client monitorClient, name string) (*synthetics.ExtendedMonitor, error) {
+	monitors, err := getAllMonitors(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*synthetics.ExtendedMonitor
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			matches = append(matches, monitor)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("error: no monitor is named %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, monitor := range matches {
+			ids[i] = monitor.ID
+		}
+		return nil, fmt.Errorf("error: %d monitors are named %q (%s); use the monitor ID instead", len(matches), name, strings.Join(ids, ", "))
+	}
+}
+
+// NRSMonitorImportState imports a Synthetics monitor by ID or by
+// name:<monitor name>. The imported state is read in full so that the
+// first plan after import is clean.
+func NRSMonitorImportState(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*providerMeta).synthetics
+
+	if strings.HasPrefix(resourceData.Id(), "name:") {
+		monitor, err := findMonitorByName(client, strings.TrimPrefix(resourceData.Id(), "name:"))
+		if err != nil {
+			return nil, err
+		}
+		resourceData.SetId(monitor.ID)
+	}
+
+	// Set the default, which isn't applied to imported resources.
+	if err := resourceData.Set("deletion_protection", false); err != nil {
+		return nil, err
+	}
+	if err := NRSMonitorRead(resourceData, meta); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{resourceData}, nil
+}
+
 // NRSMonitorCreate creates a new Synthetics monitor using Terraform
 // configuration.
 func NRSMonitorCreate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
+
+	if resourceData.Get("adopt_existing").(bool) {
+		monitor, err := findAdoptableMonitor(client, resourceData.Get("name").(string), resourceData.Get("type").(string))
+		if err != nil {
+			return err
+		}
+		if monitor != nil {
+			if !meta.(*providerMeta).claimMonitor(monitor.ID) {
+				return errors.Errorf("error: monitor %s (%q) is already managed by another nrs_monitor; a monitor can only be adopted once", monitor.ID, monitor.Name)
+			}
+			log.Printf("[INFO] adopting existing monitor %s (%s) instead of creating it", monitor.ID, monitor.Name)
+			return adoptMonitor(resourceData, meta.(*providerMeta), monitor)
+		}
+	}
 
 	args := &synthetics.CreateMonitorArgs{
 		Name:         resourceData.Get("name").(string),
@@ -162,6 +300,7 @@ func NRSMonitorCreate(resourceData *schema.ResourceData, meta interface{}) error
 
 	resourceData.SetId(monitor.ID)
 	resourceData.Set("sla_threshold", monitor.SLAThreshold)
+	meta.(*providerMeta).claimMonitor(monitor.ID)
 
 	// Set script if it was provided.
 	if data, ok := resourceData.GetOk("script"); ok {
@@ -170,31 +309,104 @@ func NRSMonitorCreate(resourceData *schema.ResourceData, meta interface{}) error
 		}
 
 		// Set script locations
-		if data, ok := resourceData.GetOk("script_locations"); ok {
-			scriptLocations := data.([]map[string]interface{})
-			for _, scriptLocation := range scriptLocations {
-				args.ScriptLocations = append(
-					args.ScriptLocations,
-					&synthetics.ScriptLocation{
-						Name: scriptLocation["name"].(string),
-						HMAC: scriptLocation["hmac"].(string),
-					},
-				)
-			}
-		}
+		args.ScriptLocations = expandScriptLocations(resourceData)
 
 		if err := client.UpdateMonitorScript(monitor.ID, args); err != nil {
 			return errors.Wrap(err, "error: could not update monitor script")
 		}
 	}
 
+	labels := mergeLabels(meta.(*providerMeta).defaultTags, resourceData.Get("labels").(map[string]interface{}))
+	if err := updateMonitorLabels(meta.(*providerMeta).monitorLabels, monitor.ID, nil, labels); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// findAdoptableMonitor returns the monitor with a name and type, or nil
+// if there isn't one. It errors if several monitors match.
+func findAdoptableMonitor(client monitorClient, name, monitorType string) (*synthetics.ExtendedMonitor, error) {
+	monitors, err := getAllMonitors(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*synthetics.ExtendedMonitor
+	for _, monitor := range monitors {
+		if monitor.Name == name && monitor.Type == monitorType {
+			matches = append(matches, monitor)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, monitor := range matches {
+			ids[i] = monitor.ID
+		}
+		return nil, errors.Errorf("error: %d %s monitors are named %q (%s), can't tell which one to adopt", len(matches), monitorType, name, strings.Join(ids, ", "))
+	}
+}
+
+// adoptMonitor takes over an existing monitor, bringing every setting,
+// its script and its labels in line with Terraform configuration.
+func adoptMonitor(resourceData *schema.ResourceData, meta *providerMeta, monitor *synthetics.ExtendedMonitor) error {
+	client := meta.synthetics
+
+	args := &synthetics.UpdateMonitorArgs{
+		Name:         resourceData.Get("name").(string),
+		Frequency:    uint(resourceData.Get("frequency").(int)),
+		URI:          resourceData.Get("uri").(string),
+		Locations:    util.StrSlice(resourceData.Get("locations").(*schema.Set).List()),
+		Status:       resourceData.Get("status").(string),
+		SLAThreshold: resourceData.Get("sla_threshold").(float64),
+	}
+	if data, ok := resourceData.GetOk("validation_string"); ok {
+		args.ValidationString = util.StrPtr(data.(string))
+	}
+	args.VerifySSL = util.BoolPtr(resourceData.Get("verify_ssl").(bool))
+	args.BypassHEADRequest = util.BoolPtr(resourceData.Get("bypass_head_request").(bool))
+	args.TreatRedirectAsFailure = util.BoolPtr(resourceData.Get("treat_redirect_as_failure").(bool))
+
+	updated, err := client.UpdateMonitor(monitor.ID, args)
+	if err != nil {
+		return errors.Wrapf(err, "error: could not update adopted monitor %s", monitor.ID)
+	}
+
+	resourceData.SetId(monitor.ID)
+	resourceData.Set("sla_threshold", updated.SLAThreshold)
+
+	if data, ok := resourceData.GetOk("script"); ok {
+		scriptArgs := &synthetics.UpdateMonitorScriptArgs{
+			ScriptText:      data.(string),
+			ScriptLocations: expandScriptLocations(resourceData),
+		}
+		if err := client.UpdateMonitorScript(monitor.ID, scriptArgs); err != nil {
+			return errors.Wrap(err, "error: could not update monitor script")
+		}
+	}
+
+	liveLabels, err := readMonitorLabels(meta.monitorLabels, monitor.ID)
+	if err != nil {
+		return err
+	}
+	labels := mergeLabels(meta.defaultTags, resourceData.Get("labels").(map[string]interface{}))
+	if err := updateMonitorLabels(meta.monitorLabels, monitor.ID, liveLabels, labels); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // NRSMonitorUpdate updates a Synthetics monitor using Terraform
 // configuration.
 func NRSMonitorUpdate(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
 
 	args := &synthetics.UpdateMonitorArgs{
 		Name:         resourceData.Get("name").(string),
@@ -232,52 +444,188 @@ func NRSMonitorUpdate(resourceData *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	if resourceData.HasChange("script") {
+	// Script locations are sent along with the script, so a change to
+	// either uploads the script again.
+	if resourceData.HasChange("script") || resourceData.HasChange("script_locations") {
 		script := resourceData.Get("script").(string)
-		scriptArgs := &synthetics.UpdateMonitorScriptArgs{
-			ScriptText: script,
-		}
 
-		if data, ok := resourceData.GetOk("script_locations"); ok {
-			scriptLocations := data.([]map[string]interface{})
-			for _, scriptLocation := range scriptLocations {
-				scriptArgs.ScriptLocations = append(
-					scriptArgs.ScriptLocations,
-					&synthetics.ScriptLocation{
-						Name: scriptLocation["name"].(string),
-						HMAC: scriptLocation["hmac"].(string),
-					},
-				)
+		// The state only holds a hash of an unchanged script, so the
+		// script is uploaded again as it is in New Relic.
+		if !resourceData.HasChange("script") {
+			script, err = client.GetMonitorScript(resourceData.Id())
+			if err != nil {
+				return errors.Wrap(err, "error: could not get monitor script")
 			}
 		}
 
+		scriptArgs := &synthetics.UpdateMonitorScriptArgs{
+			ScriptText:      script,
+			ScriptLocations: expandScriptLocations(resourceData),
+		}
+
 		if err := client.UpdateMonitorScript(resourceData.Id(), scriptArgs); err != nil {
 			return errors.Wrapf(err, "error: could not update monitor script")
 		}
 	}
 
+	if resourceData.HasChange("labels") || resourceData.HasChange("tags_all") {
+		oldLabels, _ := resourceData.GetChange("tags_all")
+		newLabels := mergeLabels(meta.(*providerMeta).defaultTags, resourceData.Get("labels").(map[string]interface{}))
+		if err := updateMonitorLabels(
+			meta.(*providerMeta).monitorLabels,
+			resourceData.Id(),
+			oldLabels.(map[string]interface{}),
+			newLabels,
+		); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// updateMonitorLabels adds and removes labels so that a monitor's
+// labels go from oldLabels to newLabels. A category whose label changes
+// is removed before the new label is added. Categories are handled in
+// order, so that the same change always makes the same requests.
+func updateMonitorLabels(client monitorLabelClient, monitorID string, oldLabels, newLabels map[string]interface{}) error {
+	for _, category := range sortedCategories(oldLabels) {
+		label := oldLabels[category]
+		if newLabel, ok := newLabels[category]; ok && newLabel == label {
+			continue
+		}
+		if err := client.DeleteMonitorLabel(monitorID, &newrelic.MonitorLabel{Category: category, Label: label.(string)}); err != nil {
+			return errors.Wrapf(err, "error: could not remove label %s:%s", category, label)
+		}
+	}
+
+	for _, category := range sortedCategories(newLabels) {
+		label := newLabels[category]
+		if oldLabel, ok := oldLabels[category]; ok && oldLabel == label {
+			continue
+		}
+		if err := client.AddMonitorLabel(monitorID, &newrelic.MonitorLabel{Category: category, Label: label.(string)}); err != nil {
+			return errors.Wrapf(err, "error: could not add label %s:%s", category, label)
+		}
+	}
+
+	return nil
+}
+
+// sortedCategories returns the categories of labels in order.
+func sortedCategories(labels map[string]interface{}) []string {
+	categories := make([]string, 0, len(labels))
+	for category := range labels {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	return categories
+}
+
+// mergeLabels merges a monitor's labels into the provider's default
+// tags. Labels win over default tags in the same category.
+func mergeLabels(defaultTags map[string]string, labels map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaultTags)+len(labels))
+	for category, label := range defaultTags {
+		merged[category] = label
+	}
+	for category, label := range labels {
+		merged[category] = label
+	}
+
+	return merged
+}
+
+// resourceLabels returns the labels of a monitor that came from its
+// labels attribute rather than from the provider's default tags: every
+// label previously in labels, and every label a default tag doesn't
+// explain.
+func resourceLabels(defaultTags map[string]string, previous, labels map[string]interface{}) map[string]interface{} {
+	own := map[string]interface{}{}
+	for category, label := range labels {
+		if _, ok := previous[category]; !ok {
+			if defaultLabel, ok := defaultTags[category]; ok && defaultLabel == label {
+				continue
+			}
+		}
+		own[category] = label
+	}
+
+	return own
+}
+
+// readMonitorLabels returns a monitor's labels as a map of category to
+// label.
+func readMonitorLabels(client monitorLabelClient, monitorID string) (map[string]interface{}, error) {
+	labels, err := client.GetMonitorLabels(monitorID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error: could not get monitor labels")
+	}
+
+	m := make(map[string]interface{}, len(labels))
+	for _, label := range labels {
+		m[label.Category] = label.Label
+	}
+
+	return m, nil
+}
+
 // NRSMonitorRead updates Terraform configuration for a Synthetics monitor.
 func NRSMonitorRead(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
 
 	monitor, err := client.GetMonitor(resourceData.Id())
 	if err != nil {
 		return errors.Wrap(err, "error: could not get monitor")
 	}
 
+	previousLabels := resourceData.Get("labels").(map[string]interface{})
+	if err := readMonitor(resourceData, meta.(*providerMeta), monitor); err != nil {
+		return err
+	}
+
+	// readMonitor sets labels to every label of the monitor; split
+	// out the ones that come from default_tags.
+	labels := resourceData.Get("labels").(map[string]interface{})
+	if err := resourceData.Set("tags_all", labels); err != nil {
+		return err
+	}
+	if err := resourceData.Set("labels", resourceLabels(meta.(*providerMeta).defaultTags, previousLabels, labels)); err != nil {
+		return err
+	}
+
+	// New Relic doesn't return script locations, so they are only
+	// cleared along with the script.
+	if resourceData.Get("script").(string) == "" {
+		if err := resourceData.Set("script_locations", nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMonitor sets the attributes shared by the nrs_monitor resource
+// and data source from a monitor.
+func readMonitor(resourceData *schema.ResourceData, meta *providerMeta, monitor *synthetics.ExtendedMonitor) error {
+	client := meta.synthetics
+
+	labels, err := readMonitorLabels(meta.monitorLabels, monitor.ID)
+	if err != nil {
+		return err
+	}
+	if err := resourceData.Set("labels", labels); err != nil {
+		return err
+	}
+
 	if monitor.Type == synthetics.TypeScriptAPI || monitor.Type == synthetics.TypeScriptBrowser {
-		script, err := client.GetMonitorScript(resourceData.Id())
+		script, err := client.GetMonitorScript(monitor.ID)
 		switch err {
 		case synthetics.ErrMonitorScriptNotFound:
 			if err := resourceData.Set("script", nil); err != nil {
 				return err
 			}
-			if err := resourceData.Set("script_locations", nil); err != nil {
-				return err
-			}
 		case nil:
 			if err := resourceData.Set("script", sha256StateFunc(script)); err != nil {
 				return err
@@ -355,7 +703,11 @@ func NRSMonitorRead(resourceData *schema.ResourceData, meta interface{}) error {
 // NRSMonitorDelete deletes a Synthetics monitor using Terraform
 // configuration.
 func NRSMonitorDelete(resourceData *schema.ResourceData, meta interface{}) error {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
+
+	if resourceData.Get("deletion_protection").(bool) {
+		return errors.Errorf("error: monitor %s has deletion_protection set; set it to false and apply before deleting the monitor", resourceData.Id())
+	}
 
 	if err := client.DeleteMonitor(resourceData.Id()); err != nil {
 		return errors.Wrap(err, "error: could not delete monitor")
@@ -366,7 +718,7 @@ func NRSMonitorDelete(resourceData *schema.ResourceData, meta interface{}) error
 
 // NRSMonitorExists checks whether a Synthetics monitor exists.
 func NRSMonitorExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*synthetics.Client)
+	client := meta.(*providerMeta).synthetics
 
 	if _, err := client.GetMonitor(resourceData.Id()); err != nil {
 		if err == synthetics.ErrMonitorNotFound {
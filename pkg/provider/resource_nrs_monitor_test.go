@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	synthetics "github.com/dollarshaveclub/new-relic-synthetics-go"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/fake"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func newFakeMeta() (*fake.Client, *providerMeta) {
+	client := fake.NewClient()
+	return client, &providerMeta{
+		synthetics:                client,
+		monitorLabels:             client,
+		alertPolicies:             client,
+		locationFailureConditions: client,
+		nrqlConditions:            client,
+		locations:                 client,
+		insights:                  client,
+	}
+}
+
+func simpleMonitorConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":      "monitor",
+		"type":      "SIMPLE",
+		"frequency": 5,
+		"uri":       "https://example.com",
+		"locations": []interface{}{"AWS_US_WEST_1"},
+		"status":    "ENABLED",
+	}
+}
+
+func TestMonitorRoundTrip(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	raw := simpleMonitorConfig()
+	raw["labels"] = map[string]interface{}{"Team": "web", "Env": "prod"}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	monitor, ok := client.Monitors[state.ID]
+	if !ok {
+		t.Fatalf("expected monitor %s to be created", state.ID)
+	}
+	if len(client.Labels[state.ID]) != 2 {
+		t.Errorf("expected 2 labels, got %v", client.Labels[state.ID])
+	}
+	checkAttributes(t, state, map[string]string{
+		"name":          "monitor",
+		"type":          "SIMPLE",
+		"frequency":     "5",
+		"uri":           "https://example.com",
+		"locations.#":   "1",
+		"status":        "ENABLED",
+		"sla_threshold": "7",
+		"labels.%":      "2",
+		"labels.Team":   "web",
+		"labels.Env":    "prod",
+		"tags_all.%":    "2",
+		"tags_all.Team": "web",
+		"tags_all.Env":  "prod",
+	})
+
+	raw["frequency"] = 15
+	raw["status"] = "MUTED"
+	raw["locations"] = []interface{}{"AWS_US_WEST_1", "AWS_US_EAST_1"}
+	raw["labels"] = map[string]interface{}{"Team": "api"}
+	state = applyResource(t, resource, state, raw, meta)
+
+	monitor = client.Monitors[state.ID]
+	if monitor.Frequency != 15 || monitor.Status != "MUTED" || len(monitor.Locations) != 2 {
+		t.Errorf("expected the monitor to be updated, got %#v", monitor)
+	}
+	labels := client.Labels[state.ID]
+	if len(labels) != 1 || *labels[0] != (newrelic.MonitorLabel{Category: "Team", Label: "api"}) {
+		t.Errorf("expected only the Team:api label, got %v", labels)
+	}
+}
+
+func TestMonitorScript(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	raw := simpleMonitorConfig()
+	raw["type"] = "SCRIPT_API"
+	delete(raw, "uri")
+	raw["script"] = "console.log('check')"
+	state := applyResource(t, resource, nil, raw, meta)
+
+	if got := client.Scripts[state.ID].ScriptText; got != "console.log('check')" {
+		t.Errorf("expected the script to be uploaded, got %q", got)
+	}
+	checkAttributes(t, state, map[string]string{
+		"script": sha256StateFunc("console.log('check')"),
+	})
+
+	// Changing only the script locations uploads the live script
+	// again, not its hash.
+	raw["script_locations"] = []interface{}{
+		map[string]interface{}{"name": "private", "hmac": "secret"},
+	}
+	state = applyResource(t, resource, state, raw, meta)
+
+	script := client.Scripts[state.ID]
+	if script.ScriptText != "console.log('check')" {
+		t.Errorf("expected the script to be uploaded again unchanged, got %q", script.ScriptText)
+	}
+	if len(script.ScriptLocations) != 1 || *script.ScriptLocations[0] != (synthetics.ScriptLocation{Name: "private", HMAC: "secret"}) {
+		t.Errorf("expected the script location to be uploaded, got %v", script.ScriptLocations)
+	}
+}
+
+func TestMonitorDeletedOutOfBand(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	state := applyResource(t, resource, nil, simpleMonitorConfig(), meta)
+	delete(client.Monitors, state.ID)
+
+	state, err := resource.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state != nil {
+		t.Fatalf("expected a deleted monitor to be removed from state, got %#v", state)
+	}
+}
+
+func TestMonitorInjectedErrors(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+	errInjected := errors.New("injected")
+
+	raw, err := config.NewRawConfig(simpleMonitorConfig())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := resource.Diff(nil, terraform.NewResourceConfig(raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client.Errors["CreateMonitor"] = errInjected
+	if _, err := resource.Apply(nil, diff, meta); err == nil {
+		t.Fatal("expected a failing CreateMonitor to fail the apply")
+	}
+	if len(client.Monitors) != 0 {
+		t.Fatalf("expected no monitor, got %d", len(client.Monitors))
+	}
+	delete(client.Errors, "CreateMonitor")
+
+	state := applyResource(t, resource, nil, simpleMonitorConfig(), meta)
+
+	client.Errors["GetMonitor"] = errInjected
+	if _, err := resource.Refresh(state, meta); err == nil {
+		t.Fatal("expected a failing GetMonitor to fail the refresh")
+	}
+	delete(client.Errors, "GetMonitor")
+
+	client.Errors["DeleteMonitor"] = errInjected
+	if _, err := resource.Apply(state, &terraform.InstanceDiff{Destroy: true}, meta); err == nil {
+		t.Fatal("expected a failing DeleteMonitor to fail the destroy")
+	}
+	if _, ok := client.Monitors[state.ID]; !ok {
+		t.Fatal("expected the monitor to remain")
+	}
+}
+
+func TestMonitorAdoptExisting(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	existing, err := client.CreateMonitor(&synthetics.CreateMonitorArgs{
+		Name:      "monitor",
+		Type:      "SIMPLE",
+		Frequency: 60,
+		URI:       "https://old.example.com",
+		Locations: []string{"AWS_EU_WEST_1"},
+		Status:    "DISABLED",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.Labels[existing.ID] = []*newrelic.MonitorLabel{{Category: "Stale", Label: "yes"}}
+
+	raw := simpleMonitorConfig()
+	raw["adopt_existing"] = true
+	raw["labels"] = map[string]interface{}{"Team": "web"}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	if state.ID != existing.ID {
+		t.Fatalf("expected monitor %s to be adopted, got %s", existing.ID, state.ID)
+	}
+	if len(client.Monitors) != 1 {
+		t.Fatalf("expected no new monitor, got %d monitors", len(client.Monitors))
+	}
+	monitor := client.Monitors[existing.ID]
+	if monitor.Frequency != 5 || monitor.URI != "https://example.com" || monitor.Status != "ENABLED" {
+		t.Errorf("expected the adopted monitor to be reconciled, got %#v", monitor)
+	}
+	labels := client.Labels[existing.ID]
+	if len(labels) != 1 || labels[0].Category != "Team" {
+		t.Errorf("expected the adopted monitor's labels to be reconciled, got %v", labels)
+	}
+
+	// A second resource with the same name and type can't adopt the
+	// monitor again.
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := resource.Diff(nil, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := resource.Apply(nil, diff, meta); err == nil {
+		t.Error("expected adopting a monitor that is already managed to fail")
+	}
+
+	// Neither can a resource in a run where the monitor was created.
+	_, meta = newFakeMeta()
+	applyResource(t, resource, nil, simpleMonitorConfig(), meta)
+	if _, err := resource.Apply(nil, diff, meta); err == nil {
+		t.Error("expected adopting a monitor created in the same run to fail")
+	}
+
+	// Nor can it pick one of several matching monitors.
+	client, meta = newFakeMeta()
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateMonitor(&synthetics.CreateMonitorArgs{Name: "monitor", Type: "SIMPLE"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if _, err := resource.Apply(nil, diff, meta); err == nil {
+		t.Error("expected adopting one of several matching monitors to fail")
+	}
+	if len(client.Monitors) != 2 {
+		t.Errorf("expected no new monitor, got %d monitors", len(client.Monitors))
+	}
+}
+
+func TestMonitorDeletionProtection(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	raw := simpleMonitorConfig()
+	raw["deletion_protection"] = true
+	state := applyResource(t, resource, nil, raw, meta)
+
+	destroy := &terraform.InstanceDiff{Destroy: true}
+	if _, err := resource.Apply(state, destroy, meta); err == nil {
+		t.Fatal("expected deleting a protected monitor to fail")
+	}
+	if _, ok := client.Monitors[state.ID]; !ok {
+		t.Fatal("expected the protected monitor to remain")
+	}
+
+	// Turning protection off is an update that leaves the monitor
+	// alone, after which the monitor can be deleted.
+	raw["deletion_protection"] = false
+	state = applyResource(t, resource, state, raw, meta)
+	if _, ok := client.Monitors[state.ID]; !ok {
+		t.Fatal("expected turning off deletion protection to keep the monitor")
+	}
+	if _, err := resource.Apply(state, destroy, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := client.Monitors[state.ID]; ok {
+		t.Error("expected the unprotected monitor to be deleted")
+	}
+}
+
+func TestMonitorImportState(t *testing.T) {
+	_, meta := newFakeMeta()
+	resource := NRSMonitorResource()
+
+	raw := simpleMonitorConfig()
+	raw["type"] = "SCRIPT_API"
+	delete(raw, "uri")
+	raw["script"] = "console.log('check')"
+	raw["script_locations"] = []interface{}{
+		map[string]interface{}{"name": "private", "hmac": "secret"},
+	}
+	raw["labels"] = map[string]interface{}{"team": "web"}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	for _, importID := range []string{state.ID, "name:" + raw["name"].(string)} {
+		resourceData := resource.Data(nil)
+		resourceData.SetId(importID)
+		imported, err := NRSMonitorImportState(resourceData, meta)
+		if err != nil {
+			t.Fatalf("%s: err: %s", importID, err)
+		}
+		importedState := imported[0].State()
+
+		// Everything but the script locations, which New Relic
+		// doesn't return, is read back.
+		for key, value := range state.Attributes {
+			if strings.HasPrefix(key, "script_locations") || key == "adopt_existing" {
+				continue
+			}
+			if importedState.Attributes[key] != value {
+				t.Errorf("%s: expected %s to be %q, got %q", importID, key, value, importedState.Attributes[key])
+			}
+		}
+		if count := importedState.Attributes["script_locations.#"]; count != "" && count != "0" {
+			t.Errorf("%s: expected no script locations, got %s", importID, count)
+		}
+
+		// The first plan adds the script locations and nothing else.
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		diff, err := resource.Diff(importedState, terraform.NewResourceConfig(c))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for key := range diff.Attributes {
+			if !strings.HasPrefix(key, "script_locations") && key != "adopt_existing" {
+				t.Errorf("%s: unexpected change to %s after import", importID, key)
+			}
+		}
+		if diff.Attributes["script_locations.0.name"] == nil {
+			t.Errorf("%s: expected the script locations to be planned, got %#v", importID, diff.Attributes)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// violationTimeLimits are the violation time limits, in seconds,
+// accepted by New Relic.
+var violationTimeLimits = []int{0, 3600, 7200, 14400, 28800, 43200, 86400}
+
+// NRSMultiLocationAlertConditionResource returns a Terraform schema
+// for a New Relic Synthetics multi-location alert condition.
+func NRSMultiLocationAlertConditionResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the alert condition",
+			},
+			"policy_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The ID of the policy to attach the alert condition to",
+				ForceNew:    true,
+			},
+			"entities": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "The IDs of the monitors the alert condition applies to",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"critical_threshold": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The number of failing locations that opens a critical violation",
+			},
+			"warning_threshold": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The number of failing locations that opens a warning violation",
+			},
+			"violation_time_limit_seconds": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The number of seconds after which open violations are closed (one of 0, 3600, 7200, 14400, 28800, 43200, or 86400)",
+				ValidateFunc: validateViolationTimeLimit,
+			},
+			"runbook_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The URL to a runbook for addressing the alert",
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the alert condition is enabled",
+			},
+		},
+		Create: NRSMultiLocationAlertConditionCreate,
+		Exists: NRSMultiLocationAlertConditionExists,
+		Delete: NRSMultiLocationAlertConditionDelete,
+		Read:   NRSMultiLocationAlertConditionRead,
+		Update: NRSMultiLocationAlertConditionUpdate,
+		Importer: &schema.ResourceImporter{
+			State: NRSMultiLocationAlertConditionImportState,
+		},
+	}
+}
+
+func validateViolationTimeLimit(i interface{}, k string) ([]string, []error) {
+	v := i.(int)
+	for _, limit := range violationTimeLimits {
+		if v == limit {
+			return nil, nil
+		}
+	}
+
+	return nil, []error{fmt.Errorf("%s must be one of %v, got %d", k, violationTimeLimits, v)}
+}
+
+// multiLocationAlertCondition builds a multi-location alert condition
+// from Terraform configuration.
+func multiLocationAlertCondition(resourceData *schema.ResourceData) (*newrelic.LocationFailureCondition, error) {
+	condition := &newrelic.LocationFailureCondition{
+		Name:                      resourceData.Get("name").(string),
+		Enabled:                   resourceData.Get("enabled").(bool),
+		Entities:                  util.StrSlice(resourceData.Get("entities").(*schema.Set).List()),
+		ViolationTimeLimitSeconds: resourceData.Get("violation_time_limit_seconds").(int),
+		RunbookURL:                resourceData.Get("runbook_url").(string),
+		Terms: []*newrelic.LocationFailureTerm{
+			{
+				Priority:  newrelic.PriorityCritical,
+				Threshold: resourceData.Get("critical_threshold").(int),
+			},
+		},
+	}
+
+	if data, ok := resourceData.GetOk("warning_threshold"); ok {
+		warning := data.(int)
+		if warning >= condition.Terms[0].Threshold {
+			return nil, fmt.Errorf("error: warning_threshold (%d) must be lower than critical_threshold (%d)", warning, condition.Terms[0].Threshold)
+		}
+		condition.Terms = append(condition.Terms, &newrelic.LocationFailureTerm{
+			Priority:  newrelic.PriorityWarning,
+			Threshold: warning,
+		})
+	}
+
+	return condition, nil
+}
+
+// NRSMultiLocationAlertConditionCreate creates a multi-location alert
+// condition using Terraform configuration.
+func NRSMultiLocationAlertConditionCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).locationFailureConditions
+
+	condition, err := multiLocationAlertCondition(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err = client.CreateLocationFailureCondition(uint(resourceData.Get("policy_id").(int)), condition)
+	if err != nil {
+		return errors.Wrap(err, "error: could not create multi-location alert condition")
+	}
+
+	resourceData.SetId(fmt.Sprintf("%d", condition.ID))
+
+	return NRSMultiLocationAlertConditionRead(resourceData, meta)
+}
+
+// NRSMultiLocationAlertConditionImportState imports a multi-location
+// alert condition using policy_id:condition_id.
+func NRSMultiLocationAlertConditionImportState(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	s := strings.Split(resourceData.Id(), ":")
+	if len(s) != 2 {
+		return nil, fmt.Errorf("Import resource ID should consist of policy_id:condition_id")
+	}
+
+	policyID, err := strconv.Atoi(s[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: invalid policy ID %q", s[0])
+	}
+	if _, err := strconv.ParseUint(s[1], 10, 0); err != nil {
+		return nil, errors.Wrapf(err, "error: invalid alert condition ID %q", s[1])
+	}
+
+	resourceData.SetId(s[1])
+	if err := resourceData.Set("policy_id", policyID); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{resourceData}, nil
+}
+
+// NRSMultiLocationAlertConditionExists checks whether a
+// multi-location alert condition exists.
+func NRSMultiLocationAlertConditionExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
+	client := meta.(*providerMeta).locationFailureConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.GetLocationFailureCondition(uint(resourceData.Get("policy_id").(int)), id)
+	if err == newrelic.ErrLocationFailureConditionNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "error: could not find multi-location alert condition")
+	}
+
+	return true, nil
+}
+
+// NRSMultiLocationAlertConditionDelete deletes a multi-location alert
+// condition.
+func NRSMultiLocationAlertConditionDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).locationFailureConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteLocationFailureCondition(id)
+	if err != nil && err != newrelic.ErrLocationFailureConditionNotFound {
+		return errors.Wrap(err, "error: could not delete multi-location alert condition")
+	}
+
+	return nil
+}
+
+// NRSMultiLocationAlertConditionRead refreshes multi-location alert
+// condition information using Terraform configuration.
+func NRSMultiLocationAlertConditionRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).locationFailureConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err := client.GetLocationFailureCondition(uint(resourceData.Get("policy_id").(int)), id)
+	if err != nil {
+		return errors.Wrap(err, "error: could not find multi-location alert condition")
+	}
+
+	if err := resourceData.Set("name", condition.Name); err != nil {
+		return err
+	}
+	if err := resourceData.Set("enabled", condition.Enabled); err != nil {
+		return err
+	}
+	if err := resourceData.Set("entities", condition.Entities); err != nil {
+		return err
+	}
+	if err := resourceData.Set("violation_time_limit_seconds", condition.ViolationTimeLimitSeconds); err != nil {
+		return err
+	}
+	if err := resourceData.Set("runbook_url", condition.RunbookURL); err != nil {
+		return err
+	}
+
+	// A term that is missing from New Relic is read back as zero so
+	// that its removal shows up as drift.
+	var critical, warning int
+	for _, term := range condition.Terms {
+		switch term.Priority {
+		case newrelic.PriorityCritical:
+			critical = term.Threshold
+		case newrelic.PriorityWarning:
+			warning = term.Threshold
+		}
+	}
+	if err := resourceData.Set("critical_threshold", critical); err != nil {
+		return err
+	}
+	if err := resourceData.Set("warning_threshold", warning); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NRSMultiLocationAlertConditionUpdate updates a multi-location alert
+// condition using Terraform configuration.
+func NRSMultiLocationAlertConditionUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).locationFailureConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err := multiLocationAlertCondition(resourceData)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.UpdateLocationFailureCondition(id, condition); err != nil {
+		return errors.Wrap(err, "error: could not update multi-location alert condition")
+	}
+
+	return NRSMultiLocationAlertConditionRead(resourceData, meta)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestMultiLocationAlertCondition(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSMultiLocationAlertConditionResource()
+
+	raw := map[string]interface{}{
+		"name":               "web down",
+		"policy_id":          7,
+		"entities":           []interface{}{"monitor-a", "monitor-b"},
+		"critical_threshold": 2,
+		"enabled":            true,
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	id, err := strconv.ParseUint(state.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	created, ok := client.LocationFailureConditions[uint(id)]
+	if !ok || client.LocationFailureConditionPolicies[uint(id)] != 7 {
+		t.Fatalf("expected condition %d in policy 7", id)
+	}
+	if created.Name != "web down" || len(created.Terms) != 1 || *created.Terms[0] != (newrelic.LocationFailureTerm{Priority: newrelic.PriorityCritical, Threshold: 2}) {
+		t.Errorf("unexpected condition %+v", created)
+	}
+	if state.Attributes["entities.#"] != "2" || state.Attributes["warning_threshold"] != "0" {
+		t.Errorf("unexpected state %v", state.Attributes)
+	}
+
+	raw["name"] = "web really down"
+	raw["entities"] = []interface{}{"monitor-a"}
+	raw["critical_threshold"] = 3
+	raw["warning_threshold"] = 1
+	raw["violation_time_limit_seconds"] = 3600
+	raw["runbook_url"] = "https://example.com/runbook"
+	updated := applyResource(t, resource, state, raw, meta)
+
+	if updated.ID != state.ID {
+		t.Errorf("expected the condition to be updated in place, got ID %s", updated.ID)
+	}
+	condition := client.LocationFailureConditions[uint(id)]
+	expected := &newrelic.LocationFailureCondition{
+		ID:                        uint(id),
+		Name:                      "web really down",
+		Enabled:                   true,
+		Entities:                  []string{"monitor-a"},
+		ViolationTimeLimitSeconds: 3600,
+		RunbookURL:                "https://example.com/runbook",
+		Terms: []*newrelic.LocationFailureTerm{
+			{Priority: newrelic.PriorityCritical, Threshold: 3},
+			{Priority: newrelic.PriorityWarning, Threshold: 1},
+		},
+	}
+	if !reflect.DeepEqual(condition, expected) {
+		t.Errorf("expected %+v, got %+v", expected, condition)
+	}
+	for key, value := range map[string]string{
+		"name":                         "web really down",
+		"entities.#":                   "1",
+		"critical_threshold":           "3",
+		"warning_threshold":            "1",
+		"violation_time_limit_seconds": "3600",
+		"runbook_url":                  "https://example.com/runbook",
+	} {
+		if updated.Attributes[key] != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, updated.Attributes[key])
+		}
+	}
+
+	// Changes made in New Relic are read back.
+	condition.Terms = condition.Terms[:1]
+	condition.Enabled = false
+	refreshed, err := resource.Refresh(updated, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if refreshed.Attributes["warning_threshold"] != "0" || refreshed.Attributes["enabled"] != "false" {
+		t.Errorf("expected the removed warning term and disabled condition to be read back, got %v", refreshed.Attributes)
+	}
+}
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dollarshaveclub/new-relic-synthetics-go/util"
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// NRSSyntheticsNRQLConditionResource returns a Terraform schema for a
+// NRQL alert condition over New Relic Synthetics check results.
+func NRSSyntheticsNRQLConditionResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the alert condition",
+			},
+			"policy_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The ID of the policy to attach the alert condition to",
+				ForceNew:    true,
+			},
+			"monitor_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the monitor whose check results are queried",
+			},
+			"metric": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The metric to alert on (one of duration, failure_rate, request_duration)",
+				ValidateFunc:  validation.StringInSlice(nrqlMetrics, false),
+				ConflictsWith: []string{"nrql"},
+			},
+			"percentile": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The percentile of durations to alert on; durations are averaged when unset",
+				ValidateFunc: validation.IntBetween(1, 99),
+			},
+			"locations": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The locations whose check results are queried; all locations when unset",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"nrql": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "A raw NRQL query, used instead of metric",
+				ConflictsWith: []string{"metric", "percentile", "locations"},
+			},
+			"query": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The NRQL query evaluated by the alert condition",
+			},
+			"since_value": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				Description:  "The number of minutes the query looks back",
+				ValidateFunc: validation.IntBetween(1, 20),
+			},
+			"operator": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "above",
+				Description:  "How the query result is compared with the thresholds (one of above, below, equal)",
+				ValidateFunc: validation.StringInSlice([]string{"above", "below", "equal"}, false),
+			},
+			"threshold_duration": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				Description:  "The number of minutes a threshold must be crossed for before a violation is opened",
+				ValidateFunc: validation.IntBetween(1, 120),
+			},
+			"time_function": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "all",
+				Description:  "Whether the threshold must be crossed for the whole duration or at least once (one of all, any)",
+				ValidateFunc: validation.StringInSlice([]string{"all", "any"}, false),
+			},
+			"critical_threshold": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Required:    true,
+				Description: "The value that opens a critical violation; durations are in milliseconds",
+			},
+			"warning_threshold": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "The value that opens a warning violation; durations are in milliseconds",
+			},
+			"violation_time_limit_seconds": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The number of seconds after which open violations are closed (one of 0, 3600, 7200, 14400, 28800, 43200, or 86400)",
+				ValidateFunc: validateViolationTimeLimit,
+			},
+			"runbook_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The URL to a runbook for addressing the alert",
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the alert condition is enabled",
+			},
+		},
+		Create: NRSSyntheticsNRQLConditionCreate,
+		Exists: NRSSyntheticsNRQLConditionExists,
+		Delete: NRSSyntheticsNRQLConditionDelete,
+		Read:   NRSSyntheticsNRQLConditionRead,
+		Update: NRSSyntheticsNRQLConditionUpdate,
+		Importer: &schema.ResourceImporter{
+			State: NRSSyntheticsNRQLConditionImportState,
+		},
+	}
+}
+
+// syntheticsNRQLConditionQuery returns the NRQL query described by
+// Terraform configuration.
+func syntheticsNRQLConditionQuery(resourceData *schema.ResourceData) (string, error) {
+	if data, ok := resourceData.GetOk("nrql"); ok {
+		return data.(string), nil
+	}
+
+	metric, ok := resourceData.GetOk("metric")
+	if !ok {
+		return "", errors.New("error: one of metric or nrql must be set")
+	}
+
+	return syntheticsNRQL(
+		metric.(string),
+		resourceData.Get("monitor_id").(string),
+		resourceData.Get("percentile").(int),
+		util.StrSlice(resourceData.Get("locations").(*schema.Set).List()),
+	)
+}
+
+// syntheticsNRQLCondition builds a NRQL alert condition from Terraform
+// configuration.
+func syntheticsNRQLCondition(resourceData *schema.ResourceData) (*newrelic.NRQLCondition, error) {
+	query, err := syntheticsNRQLConditionQuery(resourceData)
+	if err != nil {
+		return nil, err
+	}
+
+	term := func(priority string, threshold float64) *newrelic.NRQLTerm {
+		return &newrelic.NRQLTerm{
+			Duration:     strconv.Itoa(resourceData.Get("threshold_duration").(int)),
+			Operator:     resourceData.Get("operator").(string),
+			Priority:     priority,
+			Threshold:    strconv.FormatFloat(threshold, 'f', -1, 64),
+			TimeFunction: resourceData.Get("time_function").(string),
+		}
+	}
+
+	condition := &newrelic.NRQLCondition{
+		Type:          "static",
+		Name:          resourceData.Get("name").(string),
+		Enabled:       resourceData.Get("enabled").(bool),
+		RunbookURL:    resourceData.Get("runbook_url").(string),
+		ValueFunction: "single_value",
+		NRQL: newrelic.NRQLQuery{
+			Query:      query,
+			SinceValue: strconv.Itoa(resourceData.Get("since_value").(int)),
+		},
+		ViolationTimeLimitSeconds: resourceData.Get("violation_time_limit_seconds").(int),
+		Terms: []*newrelic.NRQLTerm{
+			term(newrelic.PriorityCritical, resourceData.Get("critical_threshold").(float64)),
+		},
+	}
+	if data, ok := resourceData.GetOk("warning_threshold"); ok {
+		condition.Terms = append(condition.Terms, term(newrelic.PriorityWarning, data.(float64)))
+	}
+
+	return condition, nil
+}
+
+// NRSSyntheticsNRQLConditionCreate creates a NRQL alert condition
+// using Terraform configuration.
+func NRSSyntheticsNRQLConditionCreate(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).nrqlConditions
+
+	condition, err := syntheticsNRQLCondition(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err = client.CreateNRQLCondition(uint(resourceData.Get("policy_id").(int)), condition)
+	if err != nil {
+		return errors.Wrap(err, "error: could not create nrql alert condition")
+	}
+
+	resourceData.SetId(fmt.Sprintf("%d", condition.ID))
+
+	return NRSSyntheticsNRQLConditionRead(resourceData, meta)
+}
+
+// NRSSyntheticsNRQLConditionImportState imports a NRQL alert condition
+// using policy_id:condition_id. Read imports its query as a metric when
+// the provider could have generated it, and as nrql otherwise.
+func NRSSyntheticsNRQLConditionImportState(resourceData *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	s := strings.Split(resourceData.Id(), ":")
+	if len(s) != 2 {
+		return nil, fmt.Errorf("Import resource ID should consist of policy_id:condition_id")
+	}
+
+	policyID, err := strconv.Atoi(s[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: invalid policy ID %q", s[0])
+	}
+	conditionID, err := strconv.ParseUint(s[1], 10, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error: invalid alert condition ID %q", s[1])
+	}
+
+	if _, err := meta.(*providerMeta).nrqlConditions.GetNRQLCondition(uint(policyID), uint(conditionID)); err != nil {
+		return nil, errors.Wrap(err, "error: could not find nrql alert condition")
+	}
+
+	resourceData.SetId(s[1])
+	if err := resourceData.Set("policy_id", policyID); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{resourceData}, nil
+}
+
+// NRSSyntheticsNRQLConditionExists checks whether a NRQL alert
+// condition exists.
+func NRSSyntheticsNRQLConditionExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
+	client := meta.(*providerMeta).nrqlConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.GetNRQLCondition(uint(resourceData.Get("policy_id").(int)), id)
+	if err == newrelic.ErrNRQLConditionNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "error: could not find nrql alert condition")
+	}
+
+	return true, nil
+}
+
+// NRSSyntheticsNRQLConditionDelete deletes a NRQL alert condition.
+func NRSSyntheticsNRQLConditionDelete(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).nrqlConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteNRQLCondition(id)
+	if err != nil && err != newrelic.ErrNRQLConditionNotFound {
+		return errors.Wrap(err, "error: could not delete nrql alert condition")
+	}
+
+	return nil
+}
+
+// NRSSyntheticsNRQLConditionRead refreshes NRQL alert condition
+// information using Terraform configuration.
+func NRSSyntheticsNRQLConditionRead(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).nrqlConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err := client.GetNRQLCondition(uint(resourceData.Get("policy_id").(int)), id)
+	if err != nil {
+		return errors.Wrap(err, "error: could not find nrql alert condition")
+	}
+
+	if err := resourceData.Set("name", condition.Name); err != nil {
+		return err
+	}
+	if err := resourceData.Set("enabled", condition.Enabled); err != nil {
+		return err
+	}
+	if err := resourceData.Set("runbook_url", condition.RunbookURL); err != nil {
+		return err
+	}
+	if err := resourceData.Set("violation_time_limit_seconds", condition.ViolationTimeLimitSeconds); err != nil {
+		return err
+	}
+	if err := resourceData.Set("query", condition.NRQL.Query); err != nil {
+		return err
+	}
+
+	sinceValue, err := strconv.Atoi(condition.NRQL.SinceValue)
+	if err != nil {
+		return errors.Wrapf(err, "error: invalid since_value %q", condition.NRQL.SinceValue)
+	}
+	if err := resourceData.Set("since_value", sinceValue); err != nil {
+		return err
+	}
+
+	if err := readSyntheticsNRQLConditionQuery(resourceData, condition.NRQL.Query); err != nil {
+		return err
+	}
+
+	var critical, warning float64
+	for _, term := range condition.Terms {
+		threshold, err := strconv.ParseFloat(term.Threshold, 64)
+		if err != nil {
+			return errors.Wrapf(err, "error: invalid threshold %q", term.Threshold)
+		}
+
+		switch term.Priority {
+		case newrelic.PriorityCritical:
+			critical = threshold
+
+			duration, err := strconv.Atoi(term.Duration)
+			if err != nil {
+				return errors.Wrapf(err, "error: invalid duration %q", term.Duration)
+			}
+			if err := resourceData.Set("threshold_duration", duration); err != nil {
+				return err
+			}
+			if err := resourceData.Set("operator", term.Operator); err != nil {
+				return err
+			}
+			if err := resourceData.Set("time_function", term.TimeFunction); err != nil {
+				return err
+			}
+		case newrelic.PriorityWarning:
+			warning = threshold
+		}
+	}
+	if err := resourceData.Set("critical_threshold", critical); err != nil {
+		return err
+	}
+	if err := resourceData.Set("warning_threshold", warning); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// readSyntheticsNRQLConditionQuery sets the arguments a NRQL alert
+// condition's query was built from. A raw query is read back as nrql.
+// A generated query is read back as the metric, monitor_id, percentile
+// and locations it was built from, so that a change made in New Relic
+// shows up in the plan; if it was changed into a query the provider
+// doesn't generate, it is read back as nrql instead.
+func readSyntheticsNRQLConditionQuery(resourceData *schema.ResourceData, query string) error {
+	if _, ok := resourceData.GetOk("nrql"); ok {
+		return resourceData.Set("nrql", query)
+	}
+
+	metric, monitorID, percentile, locations, ok := parseSyntheticsNRQL(query)
+	if !ok {
+		if err := resourceData.Set("metric", ""); err != nil {
+			return err
+		}
+		return resourceData.Set("nrql", query)
+	}
+
+	if err := resourceData.Set("metric", metric); err != nil {
+		return err
+	}
+	if err := resourceData.Set("monitor_id", monitorID); err != nil {
+		return err
+	}
+	if err := resourceData.Set("percentile", percentile); err != nil {
+		return err
+	}
+
+	return resourceData.Set("locations", locations)
+}
+
+// NRSSyntheticsNRQLConditionUpdate updates a NRQL alert condition
+// using Terraform configuration.
+func NRSSyntheticsNRQLConditionUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).nrqlConditions
+
+	id, err := alertConditionID(resourceData)
+	if err != nil {
+		return err
+	}
+
+	condition, err := syntheticsNRQLCondition(resourceData)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.UpdateNRQLCondition(id, condition); err != nil {
+		return errors.Wrap(err, "error: could not update nrql alert condition")
+	}
+
+	return NRSSyntheticsNRQLConditionRead(resourceData, meta)
+}
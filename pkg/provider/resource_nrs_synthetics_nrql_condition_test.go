@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/dollarshaveclub/terraform-provider-nrs/pkg/newrelic"
+)
+
+func TestSyntheticsNRQLCondition(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSSyntheticsNRQLConditionResource()
+
+	raw := map[string]interface{}{
+		"name":               "slow checks",
+		"policy_id":          7,
+		"monitor_id":         "monitor-a",
+		"metric":             "duration",
+		"percentile":         95,
+		"locations":          []interface{}{"AWS_US_WEST_1", "AWS_US_EAST_1"},
+		"critical_threshold": 2000,
+		"warning_threshold":  1000.5,
+		"enabled":            true,
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+
+	id, err := strconv.ParseUint(state.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	condition, ok := client.NRQLConditions[uint(id)]
+	if !ok || client.NRQLConditionPolicies[uint(id)] != 7 {
+		t.Fatalf("expected condition %d in policy 7", id)
+	}
+	query := "SELECT percentile(duration, 95) FROM SyntheticCheck WHERE monitorId = 'monitor-a' AND location IN ('AWS_US_EAST_1', 'AWS_US_WEST_1')"
+	if condition.NRQL.Query != query || len(condition.Terms) != 2 || condition.Terms[1].Threshold != "1000.5" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	checkAttributes(t, state, map[string]string{
+		"query":       query,
+		"metric":      "duration",
+		"monitor_id":  "monitor-a",
+		"percentile":  "95",
+		"locations.#": "2",
+		"nrql":        "",
+	})
+
+	raw["monitor_id"] = "monitor-b"
+	raw["metric"] = "request_duration"
+	delete(raw, "percentile")
+	raw["locations"] = []interface{}{"AWS_EU_WEST_1"}
+	raw["operator"] = "below"
+	raw["since_value"] = 10
+	updated := applyResource(t, resource, state, raw, meta)
+
+	if updated.ID != state.ID {
+		t.Errorf("expected the condition to be updated in place, got ID %s", updated.ID)
+	}
+	query = "SELECT average(duration) FROM SyntheticRequest WHERE monitorId = 'monitor-b' AND location IN ('AWS_EU_WEST_1')"
+	condition = client.NRQLConditions[uint(id)]
+	if condition.NRQL.Query != query || condition.NRQL.SinceValue != "10" || condition.Terms[0].Operator != "below" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	checkAttributes(t, updated, map[string]string{
+		"query":       query,
+		"metric":      "request_duration",
+		"monitor_id":  "monitor-b",
+		"percentile":  "0",
+		"locations.#": "1",
+		"since_value": "10",
+		"operator":    "below",
+	})
+
+	// A generated query changed in New Relic is read back as the
+	// arguments it was built from.
+	condition.NRQL.Query = "SELECT percentile(duration, 50) FROM SyntheticCheck WHERE monitorId = 'monitor-c'"
+	refreshed, err := resource.Refresh(updated, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkAttributes(t, refreshed, map[string]string{
+		"metric":      "duration",
+		"monitor_id":  "monitor-c",
+		"percentile":  "50",
+		"locations.#": "0",
+		"nrql":        "",
+	})
+
+	// A query the provider doesn't generate is read back as nrql.
+	condition.NRQL.Query = "SELECT count(*) FROM SyntheticCheck WHERE monitorId = 'monitor-b'"
+	refreshed, err = resource.Refresh(updated, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkAttributes(t, refreshed, map[string]string{
+		"metric":     "",
+		"monitor_id": "monitor-b",
+		"nrql":       condition.NRQL.Query,
+	})
+
+	// Applying the configuration restores the generated query.
+	applyResource(t, resource, refreshed, raw, meta)
+	if condition := client.NRQLConditions[uint(id)]; condition.NRQL.Query != query {
+		t.Errorf("expected the query to be restored, got %q", condition.NRQL.Query)
+	}
+}
+
+func TestSyntheticsNRQLConditionRaw(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSSyntheticsNRQLConditionResource()
+
+	query := "SELECT count(*) FROM SyntheticCheck WHERE result = 'FAILED'"
+	raw := map[string]interface{}{
+		"name":               "failures",
+		"policy_id":          7,
+		"nrql":               query,
+		"critical_threshold": 3,
+		"enabled":            false,
+	}
+	state := applyResource(t, resource, nil, raw, meta)
+	checkAttributes(t, state, map[string]string{
+		"nrql":    query,
+		"query":   query,
+		"metric":  "",
+		"enabled": "false",
+	})
+
+	raw["nrql"] = query + " FACET location"
+	updated := applyResource(t, resource, state, raw, meta)
+
+	id, err := strconv.ParseUint(updated.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if condition := client.NRQLConditions[uint(id)]; condition.NRQL.Query != query+" FACET location" {
+		t.Errorf("unexpected query %q", condition.NRQL.Query)
+	}
+
+	// A raw query that happens to look generated stays raw.
+	client.NRQLConditions[uint(id)].NRQL.Query = "SELECT average(duration) FROM SyntheticCheck WHERE monitorId = 'abc'"
+	refreshed, err := resource.Refresh(updated, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkAttributes(t, refreshed, map[string]string{
+		"nrql":   "SELECT average(duration) FROM SyntheticCheck WHERE monitorId = 'abc'",
+		"metric": "",
+	})
+}
+
+func TestSyntheticsNRQLConditionImportState(t *testing.T) {
+	client, meta := newFakeMeta()
+	resource := NRSSyntheticsNRQLConditionResource()
+
+	for id, query := range map[uint]string{
+		10: "SELECT percentage(count(*), WHERE result = 'FAILED') FROM SyntheticCheck WHERE monitorId = 'abc' AND location IN ('AWS_US_WEST_1')",
+		11: "SELECT count(*) FROM SyntheticCheck",
+	} {
+		client.NRQLConditions[id] = &newrelic.NRQLCondition{
+			ID:      id,
+			Name:    "condition",
+			Enabled: true,
+			NRQL:    newrelic.NRQLQuery{Query: query, SinceValue: "3"},
+			Terms: []*newrelic.NRQLTerm{
+				{Duration: "5", Operator: "above", Priority: newrelic.PriorityCritical, Threshold: "10", TimeFunction: "all"},
+			},
+		}
+		client.NRQLConditionPolicies[id] = 7
+	}
+
+	tests := []struct {
+		importID string
+		expected map[string]string
+	}{
+		{
+			importID: "7:10",
+			expected: map[string]string{
+				"policy_id":   "7",
+				"metric":      "failure_rate",
+				"monitor_id":  "abc",
+				"locations.#": "1",
+				"nrql":        "",
+			},
+		},
+		{
+			importID: "7:11",
+			expected: map[string]string{
+				"policy_id": "7",
+				"metric":    "",
+				"nrql":      "SELECT count(*) FROM SyntheticCheck",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		resourceData := resource.Data(nil)
+		resourceData.SetId(test.importID)
+
+		imported, err := NRSSyntheticsNRQLConditionImportState(resourceData, meta)
+		if err != nil {
+			t.Fatalf("%s: err: %s", test.importID, err)
+		}
+		state, err := resource.Refresh(imported[0].State(), meta)
+		if err != nil {
+			t.Fatalf("%s: err: %s", test.importID, err)
+		}
+		checkAttributes(t, state, test.expected)
+	}
+
+	resourceData := resource.Data(nil)
+	resourceData.SetId("7:12")
+	if _, err := NRSSyntheticsNRQLConditionImportState(resourceData, meta); err == nil {
+		t.Error("expected an error importing a missing condition")
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// nrqlMetricDuration is the duration of whole Synthetics checks.
+	nrqlMetricDuration = "duration"
+	// nrqlMetricFailureRate is the percentage of failed Synthetics
+	// checks.
+	nrqlMetricFailureRate = "failure_rate"
+	// nrqlMetricRequestDuration is the duration of the individual
+	// requests made by Synthetics checks.
+	nrqlMetricRequestDuration = "request_duration"
+)
+
+var nrqlMetrics = []string{nrqlMetricDuration, nrqlMetricFailureRate, nrqlMetricRequestDuration}
+
+// nrqlString quotes s as a NRQL string literal.
+func nrqlString(s string) string {
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// nrqlStringList quotes values as a sorted, comma separated list of
+// NRQL string literals.
+func nrqlStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = nrqlString(value)
+	}
+	sort.Strings(quoted)
+
+	return strings.Join(quoted, ", ")
+}
+
+// syntheticsNRQL builds a NRQL query for metric over the check
+// results of a monitor. A zero percentile averages durations. When
+// locations are given, only results from those locations are counted.
+// The query is deterministic so that it can be compared with the one
+// stored by New Relic.
+func syntheticsNRQL(metric string, monitorID string, percentile int, locations []string) (string, error) {
+	if monitorID == "" {
+		return "", fmt.Errorf("error: monitor_id is required to build a %s query", metric)
+	}
+
+	var selection, eventType string
+	switch metric {
+	case nrqlMetricDuration, nrqlMetricRequestDuration:
+		eventType = "SyntheticCheck"
+		if metric == nrqlMetricRequestDuration {
+			eventType = "SyntheticRequest"
+		}
+		selection = "average(duration)"
+		if percentile != 0 {
+			selection = fmt.Sprintf("percentile(duration, %d)", percentile)
+		}
+	case nrqlMetricFailureRate:
+		if percentile != 0 {
+			return "", fmt.Errorf("error: percentile does not apply to %s queries", metric)
+		}
+		eventType = "SyntheticCheck"
+		selection = "percentage(count(*), WHERE result = 'FAILED')"
+	default:
+		return "", fmt.Errorf("error: unknown metric %q", metric)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE monitorId = %s", selection, eventType, nrqlString(monitorID))
+	if len(locations) > 0 {
+		query += fmt.Sprintf(" AND location IN (%s)", nrqlStringList(locations))
+	}
+
+	return query, nil
+}
+
+var (
+	// syntheticsNRQLPattern matches the queries built by
+	// syntheticsNRQL.
+	syntheticsNRQLPattern = regexp.MustCompile(`^SELECT (average\(duration\)|percentile\(duration, (\d+)\)|percentage\(count\(\*\), WHERE result = 'FAILED'\)) FROM (SyntheticCheck|SyntheticRequest) WHERE monitorId = ('(?:[^'\\]|\\.)*')(?: AND location IN \((.*)\))?$`)
+	// nrqlStringPattern matches a NRQL string literal.
+	nrqlStringPattern = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+)
+
+// parseSyntheticsNRQL returns the metric, monitor ID, percentile and
+// locations a query was built from by syntheticsNRQL. ok is false for
+// any other query.
+func parseSyntheticsNRQL(query string) (metric, monitorID string, percentile int, locations []string, ok bool) {
+	match := syntheticsNRQLPattern.FindStringSubmatch(query)
+	if match == nil {
+		return "", "", 0, nil, false
+	}
+
+	switch {
+	case match[3] == "SyntheticRequest" && !strings.HasPrefix(match[1], "percentage"):
+		metric = nrqlMetricRequestDuration
+	case match[3] == "SyntheticCheck" && strings.HasPrefix(match[1], "percentage"):
+		metric = nrqlMetricFailureRate
+	case match[3] == "SyntheticCheck":
+		metric = nrqlMetricDuration
+	default:
+		return "", "", 0, nil, false
+	}
+	if match[2] != "" {
+		var err error
+		if percentile, err = strconv.Atoi(match[2]); err != nil {
+			return "", "", 0, nil, false
+		}
+	}
+
+	unquote := func(literal string) string {
+		return strings.Replace(literal[1:len(literal)-1], `\'`, "'", -1)
+	}
+	monitorID = unquote(match[4])
+	for _, literal := range nrqlStringPattern.FindAllString(match[5], -1) {
+		locations = append(locations, unquote(literal))
+	}
+
+	// Anything the pattern is looser about than syntheticsNRQL, such as
+	// the separators between locations, shows up as a different query.
+	if built, err := syntheticsNRQL(metric, monitorID, percentile, locations); err != nil || built != query {
+		return "", "", 0, nil, false
+	}
+
+	return metric, monitorID, percentile, locations, true
+}
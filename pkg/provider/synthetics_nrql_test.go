@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSyntheticsNRQL(t *testing.T) {
+	tests := []struct {
+		metric     string
+		percentile int
+		locations  []string
+		expected   string
+	}{
+		{
+			metric:   nrqlMetricDuration,
+			expected: "SELECT average(duration) FROM SyntheticCheck WHERE monitorId = 'abc'",
+		},
+		{
+			metric:     nrqlMetricDuration,
+			percentile: 95,
+			expected:   "SELECT percentile(duration, 95) FROM SyntheticCheck WHERE monitorId = 'abc'",
+		},
+		{
+			metric:    nrqlMetricRequestDuration,
+			locations: []string{"AWS_US_WEST_1", "AWS_US_EAST_1"},
+			expected:  "SELECT average(duration) FROM SyntheticRequest WHERE monitorId = 'abc' AND location IN ('AWS_US_EAST_1', 'AWS_US_WEST_1')",
+		},
+		{
+			metric:   nrqlMetricFailureRate,
+			expected: "SELECT percentage(count(*), WHERE result = 'FAILED') FROM SyntheticCheck WHERE monitorId = 'abc'",
+		},
+	}
+
+	for _, test := range tests {
+		query, err := syntheticsNRQL(test.metric, "abc", test.percentile, test.locations)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if query != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, query)
+		}
+	}
+
+	if _, err := syntheticsNRQL(nrqlMetricFailureRate, "abc", 95, nil); err == nil {
+		t.Error("expected an error for a failure_rate percentile")
+	}
+	if _, err := syntheticsNRQL(nrqlMetricDuration, "", 0, nil); err == nil {
+		t.Error("expected an error for a missing monitor ID")
+	}
+}
+
+func TestParseSyntheticsNRQL(t *testing.T) {
+	tests := []struct {
+		metric     string
+		monitorID  string
+		percentile int
+		locations  []string
+	}{
+		{metric: nrqlMetricDuration, monitorID: "abc"},
+		{metric: nrqlMetricDuration, monitorID: "abc", percentile: 95},
+		{metric: nrqlMetricRequestDuration, monitorID: "abc", percentile: 50, locations: []string{"AWS_US_EAST_1", "AWS_US_WEST_1"}},
+		{metric: nrqlMetricFailureRate, monitorID: "it's", locations: []string{"AWS_EU_WEST_1"}},
+	}
+
+	for _, test := range tests {
+		query, err := syntheticsNRQL(test.metric, test.monitorID, test.percentile, test.locations)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		metric, monitorID, percentile, locations, ok := parseSyntheticsNRQL(query)
+		if !ok {
+			t.Errorf("expected %q to parse", query)
+			continue
+		}
+		if metric != test.metric || monitorID != test.monitorID || percentile != test.percentile || !reflect.DeepEqual(locations, test.locations) {
+			t.Errorf("%q: expected %+v, got %s %s %d %v", query, test, metric, monitorID, percentile, locations)
+		}
+	}
+
+	for _, query := range []string{
+		"SELECT count(*) FROM SyntheticCheck WHERE monitorId = 'abc'",
+		"SELECT average(duration) FROM SyntheticCheck WHERE monitorId = 'abc' SINCE 1 hour ago",
+		"SELECT percentage(count(*), WHERE result = 'FAILED') FROM SyntheticRequest WHERE monitorId = 'abc'",
+		"SELECT average(duration) FROM SyntheticCheck WHERE monitorId = 'abc' AND location IN ('b','a')",
+	} {
+		if _, _, _, _, ok := parseSyntheticsNRQL(query); ok {
+			t.Errorf("expected %q not to parse", query)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package recorder records the HTTP traffic of tests against New Relic
+// into cassettes and replays it offline.
+//
+// In record mode, requests go to New Relic and every request and
+// response is written to a cassette when the recorder stops. API keys,
+// HMACs and other secure values are redacted before anything is
+// written. In replay mode, requests are answered from the cassette in
+// the order they were recorded, and any request that wasn't recorded
+// fails the test.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Mode is whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Replay answers requests from a cassette.
+	Replay Mode = iota
+	// Record sends requests on and writes them to a cassette.
+	Record
+)
+
+// RecordEnv is the environment variable that switches tests to record
+// mode.
+const RecordEnv = "NRS_RECORD"
+
+// ModeFromEnv returns Record if RecordEnv is set, Replay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) != "" {
+		return Record
+	}
+	return Replay
+}
+
+// Redacted replaces secure values in cassettes.
+const Redacted = "REDACTED"
+
+// redactedFields are JSON fields whose values are redacted wherever
+// they appear in a body.
+var redactedFields = map[string]bool{
+	"hmac":     true,
+	"apiKey":   true,
+	"api_key":  true,
+	"password": true,
+	"secret":   true,
+}
+
+// ignoredResponseHeaders change between runs and aren't recorded.
+var ignoredResponseHeaders = []string{"Date", "Set-Cookie", "X-Request-Id"}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Request headers, which carry the API
+// keys, aren't recorded.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+type Recorder struct {
+	t       testing.TB
+	path    string
+	mode    Mode
+	next    http.RoundTripper
+	secrets []string
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     int
+}
+
+// New returns a Recorder for the cassette at path. In record mode,
+// requests are sent with next, or http.DefaultTransport if next is
+// nil. Every occurrence of a secret, such as an API key, is redacted
+// from the cassette. In replay mode, a missing cassette fails the test.
+func New(t testing.TB, path string, mode Mode, next http.RoundTripper, secrets ...string) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{t: t, path: path, mode: mode, next: next}
+	for _, secret := range secrets {
+		if secret != "" {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+
+	if mode == Replay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("could not read cassette (record it with %s=1): %s", RecordEnv, err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			t.Fatalf("could not decode cassette %s: %s", path, err)
+		}
+	}
+
+	return r
+}
+
+// Client returns an HTTP client that sends requests through the
+// Recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorded := Request{
+		Method: req.Method,
+		URL:    r.scrub(req.URL.String()),
+		Body:   r.scrubBody(body),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Replay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := http.Header{}
+	for key, values := range resp.Header {
+		if containsFold(ignoredResponseHeaders, key) {
+			continue
+		}
+		for _, value := range values {
+			header.Add(key, r.scrub(value))
+		}
+	}
+	r.interactions = append(r.interactions, &Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.scrubBody(respBody),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	if r.replayed >= len(r.interactions) {
+		r.t.Errorf("unexpected request %s %s: all %d recorded requests were made", recorded.Method, recorded.URL, len(r.interactions))
+		return nil, fmt.Errorf("recorder: unexpected request %s %s", recorded.Method, recorded.URL)
+	}
+
+	interaction := r.interactions[r.replayed]
+	if interaction.Request != recorded {
+		r.t.Errorf("unexpected request %d:\n got %s %s %s\nwant %s %s %s",
+			r.replayed, recorded.Method, recorded.URL, recorded.Body,
+			interaction.Request.Method, interaction.Request.URL, interaction.Request.Body)
+		return nil, fmt.Errorf("recorder: unexpected request %s %s", recorded.Method, recorded.URL)
+	}
+	r.replayed++
+
+	header := http.Header{}
+	for key, values := range interaction.Response.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Stop writes the cassette in record mode. In replay mode, it fails
+// the test if recorded requests weren't made.
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Replay {
+		if r.replayed < len(r.interactions) {
+			r.t.Errorf("%d of %d recorded requests were not made", len(r.interactions)-r.replayed, len(r.interactions))
+		}
+		return
+	}
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		r.t.Fatalf("could not encode cassette: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		r.t.Fatalf("could not write cassette: %s", err)
+	}
+	if err := ioutil.WriteFile(r.path, append(data, '\n'), 0644); err != nil {
+		r.t.Fatalf("could not write cassette: %s", err)
+	}
+}
+
+// scrub redacts secrets from a string.
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.Replace(s, secret, Redacted, -1)
+	}
+	return s
+}
+
+// scrubBody redacts secrets and secure fields from a body. JSON bodies
+// are re-encoded with sorted keys so that they compare equal however
+// they were encoded.
+func (r *Recorder) scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return r.scrub(string(body))
+	}
+	data, err := json.Marshal(redactFields(v))
+	if err != nil {
+		return r.scrub(string(body))
+	}
+
+	return r.scrub(string(data))
+}
+
+// redactFields redacts the values of redactedFields in decoded JSON.
+func redactFields(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactedFields[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = redactFields(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactFields(value)
+		}
+	}
+	return v
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
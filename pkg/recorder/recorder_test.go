@@ -0,0 +1,116 @@
+package recorder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// errorsT records the errors a Recorder reports.
+type errorsT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *errorsT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return dir
+}
+
+func get(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string, error) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("X-Api-Key", "secret-key")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return resp, string(data), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/v3/monitors/secret-key-monitor")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"scriptLocations":[{"name":"private","hmac":"abc"}]}`)
+	}))
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	body := `{"name":"monitor","scriptLocations":[{"hmac":"abc","name":"private"}]}`
+
+	rec := New(t, path, Record, nil, "secret-key")
+	if _, _, err := get(t, rec.Client(), http.MethodPost, server.URL+"/v3/monitors", body); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	rec.Stop()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, secret := range []string{"secret-key", "abc"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected %q to be redacted from the cassette:\n%s", secret, data)
+		}
+	}
+
+	// Replay offline, with the server gone.
+	server.Close()
+	rec = New(t, path, Replay, nil, "secret-key")
+	resp, respBody, err := get(t, rec.Client(), http.MethodPost, server.URL+"/v3/monitors", body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Location") != "/v3/monitors/REDACTED-monitor" {
+		t.Errorf("expected the recorded response, got %d %v", resp.StatusCode, resp.Header)
+	}
+	if !strings.Contains(respBody, `"hmac":"REDACTED"`) {
+		t.Errorf("expected the recorded body, got %s", respBody)
+	}
+	rec.Stop()
+}
+
+func TestReplayUnexpectedRequest(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	if err := ioutil.WriteFile(path, []byte(`[{"request":{"method":"GET","url":"https://example.com/a"},"response":{"status_code":200}}]`), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	et := &errorsT{TB: t}
+	rec := New(et, path, Replay, nil)
+	if _, _, err := get(t, rec.Client(), http.MethodGet, "https://example.com/b", ""); err == nil {
+		t.Error("expected an unexpected request to fail")
+	}
+	rec.Stop()
+
+	if len(et.errors) != 2 {
+		t.Errorf("expected the unexpected request and the unmade request to fail the test, got %v", et.errors)
+	}
+}